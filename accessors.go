@@ -0,0 +1,289 @@
+package jsonparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// IsNull reports whether the value is null
+func (v JSONValue) IsNull() bool {
+	return v.Kind == KindNull
+}
+
+// IsBool reports whether the value is a boolean
+func (v JSONValue) IsBool() bool {
+	return v.Kind == KindBool
+}
+
+// IsNumber reports whether the value is a number
+func (v JSONValue) IsNumber() bool {
+	return v.Kind == KindNumber
+}
+
+// IsString reports whether the value is a string
+func (v JSONValue) IsString() bool {
+	return v.Kind == KindString
+}
+
+// IsArray reports whether the value is an array
+func (v JSONValue) IsArray() bool {
+	return v.Kind == KindArray
+}
+
+// IsObject reports whether the value is an object
+func (v JSONValue) IsObject() bool {
+	return v.Kind == KindObject
+}
+
+// AsString returns the value of a string
+func (v JSONValue) AsString() (string, error) {
+	if v.Kind != KindString {
+		return "", typeError(KindString, v)
+	}
+
+	return v.Value.(string), nil
+}
+
+// AsFloat64 returns the value of a number
+func (v JSONValue) AsFloat64() (float64, error) {
+	if v.Kind != KindNumber {
+		return 0, typeError(KindNumber, v)
+	}
+	if num, ok := v.Value.(Number); ok {
+		return num.Float64()
+	}
+
+	return v.Value.(float64), nil
+}
+
+// AsNumber returns the value of a number in its textual form, which is the
+// original input text when the number was parsed with UseNumber
+func (v JSONValue) AsNumber() (Number, error) {
+	if v.Kind != KindNumber {
+		return "", typeError(KindNumber, v)
+	}
+	if num, ok := v.Value.(Number); ok {
+		return num, nil
+	}
+
+	return Number(strconv.FormatFloat(v.Value.(float64), 'g', -1, 64)), nil
+}
+
+// AsInt64 returns the value of a number that has no fractional component.
+// Numbers parsed with UseNumber are converted exactly.
+func (v JSONValue) AsInt64() (int64, error) {
+	if v.Kind != KindNumber {
+		return 0, typeError(KindNumber, v)
+	}
+	if num, ok := v.Value.(Number); ok {
+		if i, err := num.Int64(); err == nil {
+			return i, nil
+		}
+	}
+
+	num, err := v.AsFloat64()
+	if err != nil {
+		return 0, err
+	}
+	if num != math.Trunc(num) {
+		return 0, fmt.Errorf("number %v is not an integer", num)
+	}
+	if num < math.MinInt64 || num >= math.MaxInt64 {
+		return 0, fmt.Errorf("number %v overflows int64", num)
+	}
+
+	return int64(num), nil
+}
+
+// AsInt returns the value of a number that has no fractional component
+func (v JSONValue) AsInt() (int, error) {
+	num, err := v.AsInt64()
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(num)) != num {
+		return 0, fmt.Errorf("number %v overflows int", num)
+	}
+
+	return int(num), nil
+}
+
+// AsIntLenient is like AsInt but also accepts a string holding a JSON number, such as "30"
+func (v JSONValue) AsIntLenient() (int, error) {
+	if v.Kind != KindString {
+		return v.AsInt()
+	}
+
+	text := v.Value.(string)
+	if !isValidNumber([]byte(text)) {
+		return 0, fmt.Errorf("string %q is not a number", text)
+	}
+
+	return JSONValue{Kind: KindNumber, Value: Number(text)}.AsInt()
+}
+
+// AsBool returns the value of a boolean
+func (v JSONValue) AsBool() (bool, error) {
+	if v.Kind != KindBool {
+		return false, typeError(KindBool, v)
+	}
+
+	return v.Value.(bool), nil
+}
+
+// Get returns the field of an object with the given key and whether it exists
+func (v JSONValue) Get(key string) (JSONValue, bool) {
+	if v.Kind != KindObject {
+		return JSONValue{}, false
+	}
+	field, ok := v.Value.(map[string]JSONValue)[key]
+
+	return field, ok
+}
+
+// field returns the field of an object with the given key, failing for missing keys and non-objects
+func (v JSONValue) field(key string) (JSONValue, error) {
+	if v.Kind != KindObject {
+		return JSONValue{}, typeError(KindObject, v)
+	}
+	field, ok := v.Get(key)
+	if !ok {
+		return JSONValue{}, fmt.Errorf("key %q not found", key)
+	}
+
+	return field, nil
+}
+
+// GetString returns the string stored under key in an object
+func (v JSONValue) GetString(key string) (string, error) {
+	field, err := v.field(key)
+	if err != nil {
+		return "", err
+	}
+	s, err := field.AsString()
+	if err != nil {
+		return "", fmt.Errorf("key %q: %w", key, err)
+	}
+
+	return s, nil
+}
+
+// GetInt returns the integer stored under key in an object
+func (v JSONValue) GetInt(key string) (int, error) {
+	field, err := v.field(key)
+	if err != nil {
+		return 0, err
+	}
+	num, err := field.AsInt()
+	if err != nil {
+		return 0, fmt.Errorf("key %q: %w", key, err)
+	}
+
+	return num, nil
+}
+
+// GetBool returns the boolean stored under key in an object
+func (v JSONValue) GetBool(key string) (bool, error) {
+	field, err := v.field(key)
+	if err != nil {
+		return false, err
+	}
+	b, err := field.AsBool()
+	if err != nil {
+		return false, fmt.Errorf("key %q: %w", key, err)
+	}
+
+	return b, nil
+}
+
+// GetFloat returns the number stored under key in an object
+func (v JSONValue) GetFloat(key string) (float64, error) {
+	field, err := v.field(key)
+	if err != nil {
+		return 0, err
+	}
+	num, err := field.AsFloat64()
+	if err != nil {
+		return 0, fmt.Errorf("key %q: %w", key, err)
+	}
+
+	return num, nil
+}
+
+// Index returns the element of an array at position i and whether it is in range
+func (v JSONValue) Index(i int) (JSONValue, bool) {
+	if v.Kind != KindArray {
+		return JSONValue{}, false
+	}
+	array := v.Value.([]JSONValue)
+	if i < 0 || i >= len(array) {
+		return JSONValue{}, false
+	}
+
+	return array[i], true
+}
+
+// Len returns the number of elements of an array or fields of an object, and 0 for anything else
+func (v JSONValue) Len() int {
+	switch v.Kind {
+	case KindArray:
+		return len(v.Value.([]JSONValue))
+	case KindObject:
+		return len(v.Value.(map[string]JSONValue))
+	default:
+		return 0
+	}
+}
+
+// Keys returns the keys of an object in sorted order
+func (v JSONValue) Keys() ([]string, error) {
+	if v.Kind != KindObject {
+		return nil, typeError(KindObject, v)
+	}
+
+	return sortedKeys(v.Value.(map[string]JSONValue)), nil
+}
+
+// orderedKeys returns the keys of an object in insertion order, falling back
+// to sorted order when the value was built without a usable KeyOrder
+func (v JSONValue) orderedKeys() []string {
+	object := v.Value.(map[string]JSONValue)
+	if len(v.KeyOrder) == len(object) {
+		return v.KeyOrder
+	}
+
+	return sortedKeys(object)
+}
+
+// sortedKeys returns the keys of an object in lexicographic order
+func sortedKeys(object map[string]JSONValue) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Raw returns the bytes of source that the value was parsed from, for parsing
+// or copying the subtree later on. The value must have been parsed from source
+// with JSONParser.RecordOffsets set. The result shares memory with source.
+func (v JSONValue) Raw(source []byte) ([]byte, error) {
+	if v.End == 0 {
+		return nil, errors.New("value has no recorded offsets, parse with RecordOffsets")
+	}
+	if v.Start < 0 || v.Start > v.End || v.End > len(source) {
+		return nil, fmt.Errorf("offsets %d to %d are outside of the %d byte source", v.Start, v.End, len(source))
+	}
+
+	return source[v.Start:v.End], nil
+}
+
+// typeError reports an accessor called on a value of the wrong type
+func typeError(expected Kind, v JSONValue) error {
+	return &TypeError{Expected: expected, Got: v.Kind}
+}
@@ -0,0 +1,197 @@
+package jsonparser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAsString(t *testing.T) {
+	s, err := mustParse(t, `"hello"`).AsString()
+	if err != nil || s != "hello" {
+		t.Errorf("AsString() = %q, %v, want \"hello\"", s, err)
+	}
+
+	if _, err := mustParse(t, `42`).AsString(); err == nil || err.Error() != "expected string, got number" {
+		t.Errorf("AsString() on a number: got error %v", err)
+	}
+}
+
+func TestAsFloat64AndAsInt(t *testing.T) {
+	f, err := mustParse(t, `3.5`).AsFloat64()
+	if err != nil || f != 3.5 {
+		t.Errorf("AsFloat64() = %v, %v, want 3.5", f, err)
+	}
+
+	n, err := mustParse(t, `3.0`).AsInt()
+	if err != nil || n != 3 {
+		t.Errorf("AsInt() on 3.0 = %v, %v, want 3", n, err)
+	}
+	if _, err := mustParse(t, `3.5`).AsInt(); err == nil || err.Error() != "number 3.5 is not an integer" {
+		t.Errorf("AsInt() on 3.5: got error %v", err)
+	}
+
+	str := mustParse(t, `"3"`)
+	if _, err := str.AsInt(); err == nil || err.Error() != "expected number, got string" {
+		t.Errorf("AsInt() on a string: got error %v", err)
+	}
+	if _, err := str.AsFloat64(); err == nil || err.Error() != "expected number, got string" {
+		t.Errorf("AsFloat64() on a string: got error %v", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	object := mustParse(t, `{"name": "x", "n": null}`)
+	if field, ok := object.Get("name"); !ok || field.Value != "x" {
+		t.Errorf(`Get("name") = %v, %v, want "x", true`, field, ok)
+	}
+	if field, ok := object.Get("n"); !ok || !field.IsNull() {
+		t.Errorf(`Get("n") = %v, %v, want null, true`, field, ok)
+	}
+	if _, ok := object.Get("missing"); ok {
+		t.Error(`Get("missing") reported the key as present`)
+	}
+
+	if _, ok := mustParse(t, `["name"]`).Get("name"); ok {
+		t.Error("Get on an array reported a key as present")
+	}
+}
+
+func TestLen(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{`[1, "two", [3], {}]`, 4},
+		{`{"a": 1, "b": [2, 3]}`, 2},
+		{`[]`, 0},
+		{`"scalar"`, 0},
+		{`42`, 0},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.input).Len(); got != tt.want {
+			t.Errorf("Len(%s) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys, err := mustParse(t, `{"zeta": 1, "alpha": 2, "mid": 3}`).Keys()
+	if err != nil || !slices.Equal(keys, []string{"alpha", "mid", "zeta"}) {
+		t.Errorf("Keys() = %v, %v, want sorted keys", keys, err)
+	}
+
+	if _, err := mustParse(t, `[1]`).Keys(); err == nil || err.Error() != "expected object, got array" {
+		t.Errorf("Keys() on an array: got error %v", err)
+	}
+}
+
+func TestKindPredicates(t *testing.T) {
+	// Predicates in the order of the Kind constants
+	names := []string{"IsNull", "IsBool", "IsNumber", "IsString", "IsArray", "IsObject"}
+	for _, input := range []string{`null`, `true`, `1`, `"s"`, `[]`, `{}`} {
+		v := mustParse(t, input)
+		got := []bool{v.IsNull(), v.IsBool(), v.IsNumber(), v.IsString(), v.IsArray(), v.IsObject()}
+		for kind, is := range got {
+			if want := Kind(kind) == v.Kind; is != want {
+				t.Errorf("%s: %s() = %v, want %v", input, names[kind], is, want)
+			}
+		}
+	}
+}
+
+func TestGetTyped(t *testing.T) {
+	doc := mustParse(t, sampleDocument)
+	if name, err := doc.GetString("name"); err != nil || name != "John Doe" {
+		t.Errorf("GetString(name) = %q, %v, want John Doe", name, err)
+	}
+	if age, err := doc.GetInt("age"); err != nil || age != 30 {
+		t.Errorf("GetInt(age) = %d, %v, want 30", age, err)
+	}
+	if age, err := doc.GetFloat("age"); err != nil || age != 30 {
+		t.Errorf("GetFloat(age) = %v, %v, want 30", age, err)
+	}
+	if active, err := doc.GetBool("active"); err != nil || !active {
+		t.Errorf("GetBool(active) = %v, %v, want true", active, err)
+	}
+
+	tests := []struct {
+		name string
+		get  func() error
+		want string
+	}{
+		{"missing key", func() error { _, err := doc.GetString("phone"); return err }, `key "phone" not found`},
+		{"mismatch", func() error { _, err := doc.GetInt("name"); return err }, `key "name": expected number, got string`},
+		{"mismatch", func() error { _, err := doc.GetBool("age"); return err }, `key "age": expected boolean, got number`},
+		{"not an object", func() error { _, err := mustParse(t, `[]`).GetString("a"); return err }, `expected object, got array`},
+	}
+	for _, tt := range tests {
+		if err := tt.get(); err == nil || err.Error() != tt.want {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRaw(t *testing.T) {
+	source := []byte(sampleDocument)
+	p := NewJSONParser(source)
+	p.RecordOffsets = true
+	doc, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address, _ := doc.Get("address")
+	raw, err := address.Raw(source)
+	if err != nil {
+		t.Fatalf("Raw() error = %v", err)
+	}
+	want := "{\n\t\t\"city\": \"New York\",\n\t\t\"zip\": \"10001\"\n\t}"
+	if string(raw) != want {
+		t.Errorf("Raw() = %q, want %q", raw, want)
+	}
+
+	// The raw subtree parses on its own
+	reparsed, err := Parse(raw)
+	if err != nil || !reparsed.Equal(address) {
+		t.Errorf("Parse(Raw()) = %s, %v, want %s", reparsed, err, address)
+	}
+
+	unrecorded, _ := mustParse(t, sampleDocument).Get("address")
+	if _, err := unrecorded.Raw(source); err == nil || err.Error() != "value has no recorded offsets, parse with RecordOffsets" {
+		t.Errorf("Raw() without RecordOffsets: got error %v", err)
+	}
+	if _, err := address.Raw(source[:10]); err == nil {
+		t.Error("Raw() with a too short source succeeded")
+	}
+}
+
+func TestAsIntLenient(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+		err   string
+	}{
+		{`30`, 30, ""},
+		{`"30"`, 30, ""},
+		{`"30.0"`, 30, ""},
+		{`"x"`, 0, `string "x" is not a number`},
+		{`" 30"`, 0, `string " 30" is not a number`},
+		{`30.5`, 0, "number 30.5 is not an integer"},
+		{`true`, 0, "expected number, got boolean"},
+	}
+	for _, tt := range tests {
+		got, err := mustParse(t, tt.input).AsIntLenient()
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("AsIntLenient(%s) error = %v, want %q", tt.input, err, tt.err)
+			}
+		} else if err != nil || got != tt.want {
+			t.Errorf("AsIntLenient(%s) = %d, %v, want %d", tt.input, got, err, tt.want)
+		}
+	}
+
+	// The strict accessor still rejects strings
+	if _, err := mustParse(t, `"30"`).AsInt(); err == nil {
+		t.Error(`AsInt("30") succeeded, want an error`)
+	}
+}
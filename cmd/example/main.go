@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	jsonparser "github.com/pbyrne966/json-parer-go"
+)
+
+func main() {
+	// Example JSON data
+	jsonData := []byte(`{
+		"name": "John Doe",
+		"age": 30,
+		"email": "john.doe@example.com",
+		"active": true,
+		"address": {
+			"city": "New York",
+			"zip": "10001"
+		},
+		"tags": ["golang", "json", "parser", [1, 2, 3]]
+	}`)
+
+	// Parse the JSON data
+	result, err := jsonparser.Parse(jsonData)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the parsed JSON data
+	fmt.Printf("%+v\n", result)
+}
@@ -0,0 +1,48 @@
+package jsonparser
+
+import "bytes"
+
+// Segment identifies a part of the encoded output that a Colorizer can wrap
+type Segment int
+
+const (
+	SegmentKey Segment = iota
+	SegmentString
+	SegmentNumber
+	SegmentBool
+	SegmentNull
+)
+
+// Colorizer decorates encoded output, for instance with ANSI color codes for terminals
+type Colorizer interface {
+	// Color returns the text written before and after a segment
+	Color(segment Segment) (before, after string)
+}
+
+// MarshalColored is like MarshalIndent but wraps object keys and scalar values
+// in the text returned by the colorizer
+func (v JSONValue) MarshalColored(prefix, indent string, colorizer Colorizer) ([]byte, error) {
+	var buf bytes.Buffer
+	e := &encoder{prefix: prefix, indent: indent, escapeHTML: true, colorizer: colorizer}
+	if _, err := e.writeTo(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// valueSegment returns the segment written for a scalar kind, and false for arrays and objects
+func valueSegment(kind Kind) (Segment, bool) {
+	switch kind {
+	case KindString:
+		return SegmentString, true
+	case KindNumber:
+		return SegmentNumber, true
+	case KindBool:
+		return SegmentBool, true
+	case KindNull:
+		return SegmentNull, true
+	default:
+		return 0, false
+	}
+}
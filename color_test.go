@@ -0,0 +1,38 @@
+package jsonparser
+
+import "testing"
+
+// tagColorizer wraps each segment in a tag naming it
+type tagColorizer struct{}
+
+func (tagColorizer) Color(segment Segment) (before, after string) {
+	name := [...]string{"key", "str", "num", "bool", "null"}[segment]
+	return "<" + name + ">", "</" + name + ">"
+}
+
+func TestMarshalColored(t *testing.T) {
+	value := mustParse(t, `{"a": "x", "b": [1, true, null], "c": {}}`)
+	got, err := value.MarshalColored("", "  ", tagColorizer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  <key>"a"</key>: <str>"x"</str>,
+  <key>"b"</key>: [
+    <num>1</num>,
+    <bool>true</bool>,
+    <null>null</null>
+  ],
+  <key>"c"</key>: {}
+}`
+	if string(got) != want {
+		t.Errorf("MarshalColored() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Without a colorizer the output is that of MarshalIndent
+	plain, _ := value.MarshalColored("", "  ", nil)
+	indented, _ := value.MarshalIndent("", "  ")
+	if string(plain) != string(indented) {
+		t.Errorf("MarshalColored() without a colorizer =\n%s\nwant\n%s", plain, indented)
+	}
+}
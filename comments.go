@@ -0,0 +1,161 @@
+package jsonparser
+
+import "strings"
+
+// Comments holds the comments kept around a value by JSONParser.KeepComments.
+// Each comment is stored as written, including its // or /* */ markers,
+// and is written back out by Marshal, MarshalIndent and Encoder.
+type Comments struct {
+	// Before holds the comments between the previous value and this one that
+	// are not the previous value's After comments. For an object field this
+	// includes the comments in front of its key.
+	Before []string
+	// After holds the comments starting on the line the value ends on, after
+	// its ',' if there is one. For the root value it holds every comment after it.
+	After []string
+	// End holds the comments of an array or object that follow its last element
+	// on lines of their own, or all of its comments if it is empty
+	End []string
+}
+
+// comment is a comment read by skipWhitespaces that has not been attached to a value yet
+type comment struct {
+	text    string
+	ownLine bool // a newline separates the comment from the token before it
+}
+
+// ensureComments returns the value's Comments, creating them if there are none
+func (v *JSONValue) ensureComments() *Comments {
+	if v.Comments == nil {
+		v.Comments = &Comments{}
+	}
+
+	return v.Comments
+}
+
+// clone returns a copy of the comments that shares no slices with them
+func (c *Comments) clone() *Comments {
+	if c == nil {
+		return nil
+	}
+
+	return &Comments{
+		Before: append([]string(nil), c.Before...),
+		After:  append([]string(nil), c.After...),
+		End:    append([]string(nil), c.End...),
+	}
+}
+
+// takeComments removes every pending comment and returns their text
+func (p *JSONParser) takeComments() []string {
+	return p.takeCommentRun(len(p.comments))
+}
+
+// takeAfterComments removes the pending comments that start on the line of the
+// token before them and returns their text
+func (p *JSONParser) takeAfterComments() []string {
+	n := 0
+	for n < len(p.comments) && !p.comments[n].ownLine {
+		n++
+	}
+
+	return p.takeCommentRun(n)
+}
+
+// takeCommentRun removes the first n pending comments and returns their text
+func (p *JSONParser) takeCommentRun(n int) []string {
+	if n == 0 {
+		return nil
+	}
+
+	texts := make([]string, n)
+	for i := range texts {
+		texts[i] = p.comments[i].text
+	}
+	p.comments = p.comments[:copy(p.comments, p.comments[n:])]
+
+	return texts
+}
+
+// attachAfterComments gives the element that was just stored the comments on the
+// line it ends on: the field key of object, or the last element of the scratch
+// stack when object is nil
+func (p *JSONParser) attachAfterComments(object map[string]JSONValue, key string) {
+	after := p.takeAfterComments()
+	if after == nil || p.discard {
+		return
+	}
+
+	if object == nil {
+		p.elements[len(p.elements)-1].ensureComments().After = after
+		return
+	}
+	field := object[key]
+	field.ensureComments().After = after
+	object[key] = field
+}
+
+// attachEndComments gives a finished array or object, and its Before comments
+// if it has any, the comments left before its closing bracket
+func (p *JSONParser) attachEndComments(value JSONValue, before []string) JSONValue {
+	if end := p.takeComments(); end != nil {
+		value.ensureComments().End = end
+	}
+	if before != nil {
+		value.ensureComments().Before = before
+	}
+
+	return value
+}
+
+// writeComment writes a comment. In compact output a line comment is ended
+// with a newline so it does not swallow what follows it.
+func (e *encoder) writeComment(text string) {
+	e.buf.WriteString(text)
+	if !e.pretty() && strings.HasPrefix(text, "//") {
+		e.buf.WriteByte('\n')
+	}
+}
+
+// encodeBefore writes a value's Before comments, each on its own line when pretty printing
+func (e *encoder) encodeBefore(v JSONValue) {
+	if v.Comments == nil {
+		return
+	}
+	for _, text := range v.Comments.Before {
+		e.writeComment(text)
+		e.newline()
+	}
+}
+
+// encodeAfter writes a value's After comments on the line the value ends on
+func (e *encoder) encodeAfter(v JSONValue) {
+	if v.Comments == nil {
+		return
+	}
+	for i, text := range v.Comments.After {
+		if i > 0 && strings.HasPrefix(v.Comments.After[i-1], "//") {
+			// Only the root value can have a comment after a line comment
+			e.newline()
+		} else if e.pretty() {
+			e.buf.WriteByte(' ')
+		}
+		e.writeComment(text)
+	}
+}
+
+// encodeEnd writes the End comments of an array or object, each on its own line when pretty printing
+func (e *encoder) encodeEnd(v JSONValue) {
+	if v.Comments == nil {
+		return
+	}
+	for _, text := range v.Comments.End {
+		e.newline()
+		e.writeComment(text)
+	}
+}
+
+// hasEndComments reports whether an array or object has comments before its closing bracket
+func (v JSONValue) hasEndComments() bool {
+	return v.Comments != nil && len(v.Comments.End) > 0
+}
@@ -0,0 +1,94 @@
+package jsonparser
+
+import (
+	"slices"
+	"testing"
+)
+
+// annotatedDocument is a commented object in the layout MarshalIndent writes
+const annotatedDocument = `// config
+{
+  // the name
+  "name": "x", // inline
+  /* the list */
+  "list": [
+    1,
+    2 // two
+  ],
+  "empty": {
+    // nothing yet
+  }
+} // trailer`
+
+// parseComments parses input keeping its comments
+func parseComments(t *testing.T, input string) JSONValue {
+	t.Helper()
+	p := NewJSONParser([]byte(input))
+	p.AllowComments = true
+	p.KeepComments = true
+	value, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+
+	return value
+}
+
+func TestCommentsRoundTrip(t *testing.T) {
+	value := parseComments(t, annotatedDocument)
+	got, err := value.MarshalIndent("", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != annotatedDocument {
+		t.Errorf("MarshalIndent() =\n%s\nwant\n%s", got, annotatedDocument)
+	}
+
+	// Compact output keeps the comments too and parses back to the same layout
+	compact, err := value.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := parseComments(t, string(compact)).MarshalIndent("", "  ")
+	if err != nil || string(again) != annotatedDocument {
+		t.Errorf("MarshalIndent() after a compact round trip =\n%s\nwant\n%s", again, annotatedDocument)
+	}
+}
+
+func TestCommentsAttachment(t *testing.T) {
+	value := parseComments(t, annotatedDocument)
+	tests := []struct {
+		path  string
+		place func(*Comments) []string
+		want  []string
+	}{
+		{"", func(c *Comments) []string { return c.Before }, []string{"// config"}},
+		{"", func(c *Comments) []string { return c.After }, []string{"// trailer"}},
+		{"name", func(c *Comments) []string { return c.Before }, []string{"// the name"}},
+		{"name", func(c *Comments) []string { return c.After }, []string{"// inline"}},
+		{"list", func(c *Comments) []string { return c.Before }, []string{"/* the list */"}},
+		{"list[1]", func(c *Comments) []string { return c.After }, []string{"// two"}},
+		{"empty", func(c *Comments) []string { return c.End }, []string{"// nothing yet"}},
+	}
+	for _, tt := range tests {
+		v, err := value.Path(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Comments == nil {
+			t.Errorf("%q has no comments, want %q", tt.path, tt.want)
+			continue
+		}
+		if got := tt.place(v.Comments); !slices.Equal(got, tt.want) {
+			t.Errorf("%q comments = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	// Without KeepComments nothing is attached
+	p := NewJSONParser([]byte(annotatedDocument))
+	p.AllowComments = true
+	plain, err := p.Parse()
+	if err != nil || plain.Comments != nil {
+		t.Errorf("Parse() without KeepComments = %+v, %v, want no comments", plain.Comments, err)
+	}
+}
@@ -0,0 +1,36 @@
+package jsonparser
+
+import "context"
+
+// contextCheckInterval is the number of tokens read between checks for cancellation
+const contextCheckInterval = 1024
+
+// ParseContext is like Parse but stops with ctx.Err() once ctx is cancelled
+func ParseContext(ctx context.Context, data []byte) (JSONValue, error) {
+	return NewJSONParser(data).ParseContext(ctx)
+}
+
+// ParseContext is like Parse but stops with ctx.Err() once ctx is cancelled
+func (p *JSONParser) ParseContext(ctx context.Context) (JSONValue, error) {
+	if err := ctx.Err(); err != nil {
+		return JSONValue{}, err
+	}
+	p.ctx = ctx
+	defer func() { p.ctx = nil }()
+
+	return p.Parse()
+}
+
+// checkContext reports whether the parse was cancelled, looking at the context
+// only every contextCheckInterval tokens to keep the cost down
+func (p *JSONParser) checkContext() error {
+	if p.ctx == nil {
+		return nil
+	}
+	p.tokens++
+	if p.tokens%contextCheckInterval != 0 {
+		return nil
+	}
+
+	return p.ctx.Err()
+}
@@ -0,0 +1,42 @@
+package jsonparser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// cancelAfter is a context that reports itself cancelled once Err has been called n times
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestParseContext(t *testing.T) {
+	value, err := ParseContext(context.Background(), []byte(sampleDocument))
+	if err != nil || !value.Equal(mustParse(t, sampleDocument)) {
+		t.Errorf("ParseContext() = %s, %v, want the sample document", value, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ParseContext(ctx, []byte(sampleDocument)); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseContext() with a cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseContextCancelledMidParse(t *testing.T) {
+	input := "[" + strings.Repeat("1,", 10*contextCheckInterval) + "1]"
+	ctx := &cancelAfter{Context: context.Background(), n: 2}
+	if _, err := ParseContext(ctx, []byte(input)); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseContext() error = %v, want context.Canceled", err)
+	}
+}
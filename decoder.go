@@ -0,0 +1,50 @@
+package jsonparser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Decoder reads a stream of concatenated or newline delimited JSON values
+type Decoder struct {
+	parser *JSONParser
+}
+
+// NewDecoder creates a new Decoder reading from r. The stream is read as values
+// are decoded, so Decode returns each value without waiting for the ones after it.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{parser: &JSONParser{input: &bytes.Buffer{}, window: &window{stream: r}, currentToken: ""}}
+}
+
+// Decode parses the next value from the stream, returning io.EOF once no values remain
+func (d *Decoder) Decode() (JSONValue, error) {
+	d.parser.skipBOM()
+
+	// Only whitespace left means the stream is exhausted
+	if err := d.parser.skipWhitespaces(); err != nil {
+		return JSONValue{}, err
+	}
+	if d.parser.buffered(1) == 0 {
+		if err := d.parser.window.err; err != nil {
+			return JSONValue{}, fmt.Errorf("reading input: %w", err)
+		}
+		return JSONValue{}, io.EOF
+	}
+
+	return d.parser.parseRoot()
+}
+
+// ParseFirst parses the JSON value at the start of data and returns it together
+// with the bytes that follow it, so that packed values can be parsed one at a time
+func ParseFirst(data []byte) (value JSONValue, rest []byte, err error) {
+	parser := NewJSONParser(data)
+	if err := parser.startDocument(); err != nil {
+		return JSONValue{}, data, err
+	}
+	if value, err = parser.parseRoot(); err != nil {
+		return JSONValue{}, data, err
+	}
+
+	return value, data[parser.offset:], nil
+}
@@ -0,0 +1,127 @@
+package jsonparser
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestDecoderStream(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader("{\"a\":1}\n{\"b\":[2]}\n\n {\"c\":\"x\"}\n"))
+	for _, want := range []string{`{"a":1}`, `{"b":[2]}`, `{"c":"x"}`} {
+		value, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("Decode() error = %v, want %s", err, want)
+		}
+		if got := value.String(); got != want {
+			t.Errorf("Decode() = %s, want %s", got, want)
+		}
+	}
+	if _, err := decoder.Decode(); err != io.EOF {
+		t.Errorf("Decode() at end error = %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderDoesNotWaitForLaterValues(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	go w.Write([]byte("{\"a\":1}\n{\"b\":2}\n"))
+
+	decoded := make(chan string)
+	go func() {
+		defer close(decoded)
+		decoder := NewDecoder(r)
+		for i := 0; i < 2; i++ {
+			value, err := decoder.Decode()
+			if err != nil {
+				t.Errorf("Decode() error = %v", err)
+				return
+			}
+			decoded <- value.String()
+		}
+	}()
+
+	for _, want := range []string{`{"a":1}`, `{"b":2}`} {
+		select {
+		case got := <-decoded:
+			if got != want {
+				t.Errorf("Decode() = %s, want %s", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Decode() blocked waiting for input after %s", want)
+		}
+	}
+}
+
+func TestDecoderErrorPosition(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader("{\"a\":1}\n{\"b\":2}\n{\"c\" 3}\n"))
+	for i := 0; i < 2; i++ {
+		if _, err := decoder.Decode(); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+	}
+	_, err := decoder.Decode()
+	want := `expected ':' but found 3 at offset 21 (line 3, column 6)`
+	if err == nil || err.Error() != want {
+		t.Errorf("Decode() error = %v, want %q", err, want)
+	}
+}
+
+func TestDecoderErrorPositionAcrossReads(t *testing.T) {
+	// Reading a byte at a time refills the buffer for every byte
+	input := strings.Repeat("{\"a\": [1, 2]}\n", 100) + "{\"a\": [1,, 2]}"
+	decoder := NewDecoder(iotest.OneByteReader(strings.NewReader(input)))
+	for i := 0; i < 100; i++ {
+		if _, err := decoder.Decode(); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+	}
+	_, err := decoder.Decode()
+	want := `expected value but found ',' at offset 1409 (line 101, column 10)`
+	if err == nil || err.Error() != want {
+		t.Errorf("Decode() error = %v, want %q", err, want)
+	}
+}
+
+func TestDecoderReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	decoder := NewDecoder(io.MultiReader(strings.NewReader(`{"a":1} `), &failingReader{readErr}))
+	if _, err := decoder.Decode(); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if _, err := decoder.Decode(); !errors.Is(err, readErr) {
+		t.Errorf("Decode() error = %v, want %v", err, readErr)
+	}
+}
+
+// failingReader fails every read with err
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestParseFirst(t *testing.T) {
+	data := []byte(`{"a":1}{"b":2}`)
+	first, rest, err := ParseFirst(data)
+	if err != nil || first.String() != `{"a":1}` || string(rest) != `{"b":2}` {
+		t.Fatalf("ParseFirst() = %s, %q, %v, want {\"a\":1} and {\"b\":2}", first, rest, err)
+	}
+	second, rest, err := ParseFirst(rest)
+	if err != nil || second.String() != `{"b":2}` || len(rest) != 0 {
+		t.Errorf("ParseFirst() = %s, %q, %v, want {\"b\":2} and nothing left", second, rest, err)
+	}
+
+	// Whitespace after a value is left in the rest
+	if _, rest, err := ParseFirst([]byte("[1] 2")); err != nil || string(rest) != " 2" {
+		t.Errorf("ParseFirst() rest = %q, %v, want \" 2\"", rest, err)
+	}
+	if _, rest, err := ParseFirst(data[:5]); err == nil || string(rest) != `{"a":` {
+		t.Errorf("ParseFirst() of a partial value = %q, %v, want an error and the input", rest, err)
+	}
+}
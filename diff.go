@@ -0,0 +1,81 @@
+package jsonparser
+
+import "strconv"
+
+// DiffKind identifies the type of a DiffEntry
+type DiffKind int
+
+const (
+	DiffAdded DiffKind = iota
+	DiffRemoved
+	DiffChanged
+)
+
+// String returns the lowercase name of the diff kind
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return "DiffKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// DiffEntry is a single difference found by Diff
+type DiffEntry struct {
+	Kind DiffKind
+	// Path locates the value using the syntax accepted by Path, "" for the root
+	Path string
+	Old  JSONValue // value in the first tree, unset for DiffAdded
+	New  JSONValue // value in the second tree, unset for DiffRemoved
+}
+
+// Diff returns the differences that turn a into b. Objects are compared key by
+// key and arrays index by index, any other values that are not Equal, including
+// values of different kinds, are reported as changed. Entries follow the key
+// order of a, with keys only found in b after them.
+func Diff(a, b JSONValue) []DiffEntry {
+	return diff("", a, b, nil)
+}
+
+// diff appends the differences between the values found at path in both trees
+func diff(path string, a, b JSONValue, entries []DiffEntry) []DiffEntry {
+	switch {
+	case a.Kind == KindObject && b.Kind == KindObject:
+		aFields := a.Value.(map[string]JSONValue)
+		bFields := b.Value.(map[string]JSONValue)
+		for _, key := range a.orderedKeys() {
+			if field, ok := bFields[key]; ok {
+				entries = diff(keyPath(path, key), aFields[key], field, entries)
+			} else {
+				entries = append(entries, DiffEntry{Kind: DiffRemoved, Path: keyPath(path, key), Old: aFields[key]})
+			}
+		}
+		for _, key := range b.orderedKeys() {
+			if _, ok := aFields[key]; !ok {
+				entries = append(entries, DiffEntry{Kind: DiffAdded, Path: keyPath(path, key), New: bFields[key]})
+			}
+		}
+	case a.Kind == KindArray && b.Kind == KindArray:
+		aElements := a.Value.([]JSONValue)
+		bElements := b.Value.([]JSONValue)
+		for i := 0; i < len(aElements) || i < len(bElements); i++ {
+			switch {
+			case i >= len(bElements):
+				entries = append(entries, DiffEntry{Kind: DiffRemoved, Path: indexPath(path, i), Old: aElements[i]})
+			case i >= len(aElements):
+				entries = append(entries, DiffEntry{Kind: DiffAdded, Path: indexPath(path, i), New: bElements[i]})
+			default:
+				entries = diff(indexPath(path, i), aElements[i], bElements[i], entries)
+			}
+		}
+	case !a.Equal(b):
+		entries = append(entries, DiffEntry{Kind: DiffChanged, Path: path, Old: a, New: b})
+	}
+
+	return entries
+}
@@ -0,0 +1,72 @@
+package jsonparser
+
+import (
+	"slices"
+	"testing"
+)
+
+// formatDiff renders the entries of a diff as "kind path" lines
+func formatDiff(entries []DiffEntry) []string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.Kind.String() + " " + entry.Path
+	}
+
+	return lines
+}
+
+func TestDiff(t *testing.T) {
+	a := mustParse(t, `{"name": "x", "age": 30, "tags": ["a", "b"], "address": {"city": "NY"}}`)
+	b := mustParse(t, `{"name": "y", "tags": ["a"], "address": {"city": "NY", "zip": "1"}, "active": true}`)
+	want := []string{
+		"changed name",
+		"removed age",
+		"removed tags[1]",
+		"added address.zip",
+		"added active",
+	}
+	entries := Diff(a, b)
+	if got := formatDiff(entries); !slices.Equal(got, want) {
+		t.Fatalf("Diff() = %q, want %q", got, want)
+	}
+
+	changed := entries[0]
+	if changed.Old.Value != "x" || changed.New.Value != "y" {
+		t.Errorf("changed name: Old = %v, New = %v, want x and y", changed.Old.Value, changed.New.Value)
+	}
+	if removed := entries[1]; removed.Old.Value != 30.0 || removed.New.Kind != KindNull {
+		t.Errorf("removed age: Old = %v, New = %v", removed.Old.Value, removed.New)
+	}
+	if added := entries[4]; added.New.Value != true {
+		t.Errorf("added active: New = %v, want true", added.New.Value)
+	}
+}
+
+func TestDiffEqualAndKindChanges(t *testing.T) {
+	a := mustParse(t, sampleDocument)
+	if entries := Diff(a, a.Clone()); len(entries) != 0 {
+		t.Errorf("Diff() of equal documents = %q, want none", formatDiff(entries))
+	}
+
+	// A value that changes kind is changed as a whole
+	entries := Diff(mustParse(t, `{"a": [1]}`), mustParse(t, `{"a": {"0": 1}}`))
+	if got := formatDiff(entries); !slices.Equal(got, []string{"changed a"}) {
+		t.Errorf("Diff() = %q, want [changed a]", got)
+	}
+	if got := formatDiff(Diff(mustParse(t, `1`), mustParse(t, `2`))); !slices.Equal(got, []string{"changed "}) {
+		t.Errorf("Diff() of roots = %q, want the root changed", got)
+	}
+}
+
+func TestDiffQuotedKeys(t *testing.T) {
+	// Paths of keys holding '.' or '[' can be looked up again with Path
+	a := mustParse(t, `{"v1.2": {"deps[0]": "x"}}`)
+	b := mustParse(t, `{"v1.2": {"deps[0]": "y"}}`)
+	entries := Diff(a, b)
+	if got, want := formatDiff(entries), []string{`changed ["v1.2"]["deps[0]"]`}; !slices.Equal(got, want) {
+		t.Fatalf("Diff() = %q, want %q", got, want)
+	}
+	if value, err := b.Path(entries[0].Path); err != nil || value.Value != "y" {
+		t.Errorf("Path(%q) = %v, %v, want y", entries[0].Path, value, err)
+	}
+}
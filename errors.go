@@ -0,0 +1,27 @@
+package jsonparser
+
+import "fmt"
+
+// SyntaxError describes malformed input and where in the input it was found
+type SyntaxError struct {
+	Msg    string // description of the problem
+	Offset int    // byte offset of the problem in the input
+	Line   int    // 1-based line of Offset
+	Column int    // 1-based column of Offset, counted in bytes
+}
+
+// Error formats the message together with its position
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d (line %d, column %d)", e.Msg, e.Offset, e.Line, e.Column)
+}
+
+// TypeError describes a value that was used as a kind it does not have
+type TypeError struct {
+	Expected Kind
+	Got      Kind
+}
+
+// Error names the expected and the actual kind
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("expected %s, got %s", e.Expected, e.Got)
+}
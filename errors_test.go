@@ -0,0 +1,34 @@
+package jsonparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSyntaxErrorAs(t *testing.T) {
+	_, err := Parse([]byte("{\n  \"a\": [1, 2,]\n}"))
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Parse() error = %v (%T), want a *SyntaxError", err, err)
+	}
+	want := SyntaxError{Msg: "trailing comma before ']'", Offset: 15, Line: 2, Column: 14}
+	if *syntaxErr != want {
+		t.Errorf("SyntaxError = %+v, want %+v", *syntaxErr, want)
+	}
+}
+
+func TestTypeErrorAs(t *testing.T) {
+	_, err := mustParse(t, sampleDocument).GetString("age")
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("GetString() error = %v (%T), want a *TypeError", err, err)
+	}
+	if typeErr.Expected != KindString || typeErr.Got != KindNumber {
+		t.Errorf("TypeError = %+v, want string expected and number got", *typeErr)
+	}
+
+	_, err = ParseObject([]byte(`[]`))
+	if !errors.As(err, &typeErr) || typeErr.Expected != KindObject || typeErr.Got != KindArray {
+		t.Errorf("ParseObject() error = %v, want a *TypeError for an array", err)
+	}
+}
@@ -0,0 +1,113 @@
+package jsonparser
+
+// EventHandler receives the structure of a document as ParseEvents reads it.
+// Returning an error from any method stops parsing with that error.
+type EventHandler interface {
+	StartObject() error
+	EndObject() error
+	StartArray() error
+	EndArray() error
+	Key(key string) error
+	Value(value JSONValue) error
+}
+
+// ParseEvents parses a single JSON value from data, reporting each part of it to handler
+// instead of building the parsed tree
+func ParseEvents(data []byte, handler EventHandler) error {
+	return NewJSONParser(data).ParseEvents(handler)
+}
+
+// ParseEvents is like Parse but reports the document to handler instead of returning it
+func (p *JSONParser) ParseEvents(handler EventHandler) error {
+	if err := p.startDocument(); err != nil {
+		return err
+	}
+	if err := p.readNextToken(); err != nil {
+		return err
+	}
+	if err := p.emitToken(handler); err != nil {
+		return err
+	}
+
+	return p.endDocument()
+}
+
+// emitToken reports the value starting at the current token to handler
+func (p *JSONParser) emitToken(handler EventHandler) error {
+	if p.currentKind == tokenDelim {
+		switch p.currentToken {
+		case "{":
+			return p.emitObject(handler)
+		case "[":
+			return p.emitArray(handler)
+		}
+	}
+
+	// Anything else is a scalar, which parseToken builds without recursing
+	value, err := p.parseToken()
+	if err != nil {
+		return err
+	}
+
+	return handler.Value(value)
+}
+
+// emitObject reports an object, its keys and its values to handler
+func (p *JSONParser) emitObject(handler EventHandler) error {
+	if err := p.enterContainer(); err != nil {
+		return err
+	}
+	defer func() { p.depth-- }()
+
+	if err := handler.StartObject(); err != nil {
+		return err
+	}
+	if err := p.readNextToken(); err != nil {
+		return err
+	}
+	for !p.atDelim("}") {
+		key, _, err := p.readKey()
+		if err != nil {
+			return err
+		}
+		if err := handler.Key(key); err != nil {
+			return err
+		}
+		if err := p.readNextToken(); err != nil {
+			return err
+		}
+		if err := p.emitToken(handler); err != nil {
+			return err
+		}
+		if err := p.readSeparator("}"); err != nil {
+			return err
+		}
+	}
+
+	return handler.EndObject()
+}
+
+// emitArray reports an array and its elements to handler
+func (p *JSONParser) emitArray(handler EventHandler) error {
+	if err := p.enterContainer(); err != nil {
+		return err
+	}
+	defer func() { p.depth-- }()
+
+	if err := handler.StartArray(); err != nil {
+		return err
+	}
+	if err := p.readNextToken(); err != nil {
+		return err
+	}
+	for !p.atDelim("]") {
+		if err := p.emitToken(handler); err != nil {
+			return err
+		}
+		if err := p.readSeparator("]"); err != nil {
+			return err
+		}
+	}
+
+	return handler.EndArray()
+}
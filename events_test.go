@@ -0,0 +1,72 @@
+package jsonparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// recorder is an EventHandler that records every event it receives
+type recorder struct {
+	events []string
+	keys   int
+}
+
+func (r *recorder) StartObject() error { r.events = append(r.events, "{"); return nil }
+func (r *recorder) EndObject() error   { r.events = append(r.events, "}"); return nil }
+func (r *recorder) StartArray() error  { r.events = append(r.events, "["); return nil }
+func (r *recorder) EndArray() error    { r.events = append(r.events, "]"); return nil }
+
+func (r *recorder) Key(key string) error {
+	r.keys++
+	r.events = append(r.events, key+":")
+	return nil
+}
+
+func (r *recorder) Value(value JSONValue) error {
+	r.events = append(r.events, value.String())
+	return nil
+}
+
+func TestParseEventsCountsKeys(t *testing.T) {
+	var r recorder
+	if err := ParseEvents([]byte(sampleDocument), &r); err != nil {
+		t.Fatalf("ParseEvents() error = %v", err)
+	}
+	// name, age, email, active, address, city, zip and tags
+	if r.keys != 8 {
+		t.Errorf("ParseEvents() reported %d keys, want 8", r.keys)
+	}
+}
+
+func TestParseEventsOrder(t *testing.T) {
+	var r recorder
+	if err := ParseEvents([]byte(`{"a": [1, {"b": null}], "c": "x"}`), &r); err != nil {
+		t.Fatalf("ParseEvents() error = %v", err)
+	}
+	want := `{ a: [ 1 { b: null } ] c: "x" }`
+	if got := strings.Join(r.events, " "); got != want {
+		t.Errorf("ParseEvents() events = %s, want %s", got, want)
+	}
+}
+
+// stopAtKey is an EventHandler that fails on the first key
+type stopAtKey struct {
+	recorder
+}
+
+var errStop = errors.New("stop")
+
+func (s *stopAtKey) Key(string) error { return errStop }
+
+func TestParseEventsErrors(t *testing.T) {
+	var r recorder
+	want := `expected ':' but found 1 at offset 5 (line 1, column 6)`
+	if err := ParseEvents([]byte(`{"a" 1}`), &r); err == nil || err.Error() != want {
+		t.Errorf("ParseEvents() error = %v, want %q", err, want)
+	}
+
+	if err := ParseEvents([]byte(`{"a": 1}`), &stopAtKey{}); err != errStop {
+		t.Errorf("ParseEvents() error = %v, want the handler's error", err)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/pbyrne966/json-parer-go
+
+go 1.21
@@ -0,0 +1,125 @@
+package jsonparser
+
+// frame is an array or object that parseIterative is still filling in
+type frame struct {
+	isObject  bool
+	start     int // position of the array's first element on the parser's scratch stack
+	offset    int // source offset of the opening token
+	object    map[string]JSONValue
+	keyOrder  []string
+	key       string // key of the object field currently being parsed
+	keyOffset int
+	before    []string // comments in front of the container, when KeepComments is set
+}
+
+// closing returns the token that ends the frame's container
+func (f *frame) closing() string {
+	if f.isObject {
+		return "}"
+	}
+
+	return "]"
+}
+
+// closeFrame returns the finished container of a frame
+func (p *JSONParser) closeFrame(f *frame) JSONValue {
+	if f.isObject {
+		return p.span(p.attachEndComments(JSONValue{Kind: KindObject, Value: f.object, KeyOrder: f.keyOrder}, f.before), f.offset)
+	}
+	if p.discard {
+		return JSONValue{Kind: KindArray}
+	}
+
+	return p.span(p.attachEndComments(JSONValue{Kind: KindArray, Value: p.takeElements(f.start)}, f.before), f.offset)
+}
+
+// parseIterative parses a JSON value like parseValue, but keeps open arrays and
+// objects on an explicit stack instead of recursing into them
+func (p *JSONParser) parseIterative() (JSONValue, error) {
+	var stack []*frame
+	defer func(depth int) { p.depth = depth }(p.depth)
+
+	if err := p.readNextToken(); err != nil {
+		return JSONValue{}, err
+	}
+	for {
+		var value JSONValue
+		if p.currentKind == tokenDelim && (p.currentToken == "[" || p.currentToken == "{") {
+			// Open a new container and move to its first element or key
+			if err := p.enterContainer(); err != nil {
+				return JSONValue{}, err
+			}
+			f := &frame{isObject: p.currentToken == "{", start: len(p.elements), offset: p.tokenOffset, before: p.takeComments()}
+			if f.isObject && !p.discard {
+				f.object = make(map[string]JSONValue)
+			} else if !f.isObject {
+				p.reserveElements()
+			}
+			stack = append(stack, f)
+			if err := p.readNextToken(); err != nil {
+				return JSONValue{}, err
+			}
+			if !p.atDelim(f.closing()) {
+				if err := p.startElement(f); err != nil {
+					return JSONValue{}, err
+				}
+				continue
+			}
+
+			// The container was empty
+			stack = stack[:len(stack)-1]
+			p.depth--
+			value = p.closeFrame(f)
+		} else {
+			var err error
+			if value, err = p.parseToken(); err != nil {
+				return JSONValue{}, err
+			}
+		}
+
+		// Store the finished value in its parent, closing every parent that ends with it
+		for {
+			if len(stack) == 0 {
+				return value, nil
+			}
+			f := stack[len(stack)-1]
+			if f.isObject {
+				if err := p.storeField(f.object, &f.keyOrder, f.key, f.keyOffset, value); err != nil {
+					return JSONValue{}, err
+				}
+			} else if !p.discard {
+				p.elements = append(p.elements, value)
+			}
+
+			if err := p.readSeparator(f.closing()); err != nil {
+				return JSONValue{}, err
+			}
+			p.attachAfterComments(f.object, f.key)
+			if !p.atDelim(f.closing()) {
+				if err := p.startElement(f); err != nil {
+					return JSONValue{}, err
+				}
+				break
+			}
+
+			stack = stack[:len(stack)-1]
+			p.depth--
+			value = p.closeFrame(f)
+		}
+	}
+}
+
+// startElement positions the parser on the first token of the container's next value,
+// reading the key and ':' first for objects
+func (p *JSONParser) startElement(f *frame) error {
+	if !f.isObject {
+		return nil
+	}
+
+	var err error
+	if f.key, f.keyOffset, err = p.readKey(); err != nil {
+		return err
+	}
+
+	return p.readNextToken()
+}
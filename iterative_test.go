@@ -0,0 +1,56 @@
+package jsonparser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// parseWith parses input with the recursive or the iterative parser
+func parseWith(input string, iterative bool) (JSONValue, error) {
+	p := NewJSONParser([]byte(input))
+	p.Iterative = iterative
+
+	return p.Parse()
+}
+
+func TestIterativeMatchesRecursive(t *testing.T) {
+	inputs := []string{
+		sampleDocument,
+		`[[1, [2, 3]], [[]], {}]`,
+		strings.Repeat("[", 500) + strings.Repeat("]", 500),
+		`[1, {"a" [2]}]`,
+		`{"a": [1, 2}`,
+		`[1, 2`,
+	}
+	for _, input := range inputs {
+		want, wantErr := parseWith(input, false)
+		got, err := parseWith(input, true)
+		if fmt.Sprint(err) != fmt.Sprint(wantErr) {
+			t.Errorf("Iterative: Parse(%.20q) error = %v, want %v", input, err, wantErr)
+		} else if !got.Equal(want) {
+			t.Errorf("Iterative: Parse(%.20q) = %.40s, want %.40s", input, got, want)
+		}
+	}
+}
+
+// deepDocument nests arrays and objects 1000 levels deep
+var deepDocument = strings.Repeat(`{"a": [`, 500) + strings.Repeat(`]}`, 500)
+
+func BenchmarkParseDeepRecursive(b *testing.B) {
+	benchmarkParseDeep(b, false)
+}
+
+func BenchmarkParseDeepIterative(b *testing.B) {
+	benchmarkParseDeep(b, true)
+}
+
+func benchmarkParseDeep(b *testing.B, iterative bool) {
+	b.SetBytes(int64(len(deepDocument)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := parseWith(deepDocument, iterative); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,183 +1,1127 @@
-package main
+// Package jsonparser implements a small hand-written JSON parser
+package jsonparser
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
 )
 
+// Kind identifies the type of a JSON value
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindBool
+	KindNumber
+	KindString
+	KindArray
+	KindObject
+)
+
+// String returns the lowercase name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "boolean"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindArray:
+		return "array"
+	case KindObject:
+		return "object"
+	default:
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
 // JSONValue represents a JSON value
 type JSONValue struct {
-	Type  string
-	Value interface{}
+	Kind     Kind
+	Value    interface{}
+	KeyOrder []string // object keys in the order they first appeared
+	// Start and End are the byte offsets of the value in the source, with End
+	// just past its last byte. They are only set when JSONParser.RecordOffsets is.
+	Start int
+	End   int
+	// Comments are the comments around the value, only set when JSONParser.KeepComments is
+	Comments *Comments
 }
 
-// JSONParser represents the custom JSON parser
+// DefaultMaxDepth is the nesting limit used when JSONParser.MaxDepth is zero
+const DefaultMaxDepth = 10000
+
+// delimiters holds the structural characters of JSON
+const delimiters = "{}[]:,"
+
+// tokenKind classifies the token most recently read by readNextToken
+type tokenKind int
+
+const (
+	tokenDelim   tokenKind = iota // one of { } [ ] : ,
+	tokenLiteral                  // null, true or false
+	tokenString
+	tokenNumber
+	tokenIdentifier // bare word, only read when AllowUnquotedKeys is set
+)
+
+// JSONParser represents the custom JSON parser.
+// Its exported fields configure parsing and may be set before calling Parse.
 type JSONParser struct {
+	// DisallowDuplicateKeys rejects objects that repeat a key instead of keeping the last value
+	DisallowDuplicateKeys bool
+	// AllowTrailingCommas accepts a comma directly before a closing ']' or '}'
+	AllowTrailingCommas bool
+	// UseNumber stores numbers as Number, keeping their exact text, instead of float64
+	UseNumber bool
+	// AllowControlCharacters accepts raw control characters such as tabs and newlines inside strings
+	AllowControlCharacters bool
+	// AllowSingleQuotes accepts 'single quoted' strings in addition to double quoted ones
+	AllowSingleQuotes bool
+	// AllowUnquotedKeys accepts object keys written as bare identifiers, such as
+	// {name: "x"}, made of ASCII letters, digits, '_' and '$'
+	AllowUnquotedKeys bool
+	// AllowComments treats // line comments and /* */ block comments as whitespace
+	AllowComments bool
+	// KeepComments attaches the comments accepted by AllowComments to the parsed
+	// values so they are written out again, see Comments
+	KeepComments bool
+	// ValidateUTF8 rejects strings and keys that contain invalid UTF-8
+	ValidateUTF8 bool
+	// ExtraWhitespace lists bytes such as "\f\v" that are skipped between tokens
+	// in addition to space, tab, carriage return and newline
+	ExtraWhitespace string
+	// AllowNonFiniteNumbers accepts the bare tokens NaN, Infinity and -Infinity as numbers
+	AllowNonFiniteNumbers bool
+	// Iterative parses with an explicit stack instead of recursion, which keeps
+	// deeply nested documents from growing the goroutine stack
+	Iterative bool
+	// RecordOffsets sets the Start and End offsets of every parsed value
+	RecordOffsets bool
+	// InternKeys makes objects share a single string for each distinct key instead
+	// of keeping a copy per occurrence, which saves memory on arrays of similar records
+	InternKeys bool
+	// MaxDepth limits how deeply arrays and objects may nest, DefaultMaxDepth when zero
+	MaxDepth int
+	// MaxStringLength rejects strings and keys that decode to more than this many bytes, unlimited when zero
+	MaxStringLength int
+	// MaxInputBytes rejects inputs longer than this many bytes, unlimited when zero.
+	// A reader is not read past the limit.
+	MaxInputBytes int
+
 	input        *bytes.Buffer
+	source       []byte    // complete input, used to report lines and columns
+	reader       io.Reader // source still to be read into input, if any
+	window       *window   // source read into input piece by piece, if any
 	currentToken string
+	currentKind  tokenKind
+	offset       int               // number of bytes consumed from input
+	tokenOffset  int               // offset at which currentToken starts
+	depth        int               // number of arrays and objects currently open
+	discard      bool              // only check syntax, without building objects and arrays
+	elements     []JSONValue       // scratch stack collecting the elements of open arrays
+	scratch      []byte            // reused buffer for assembling string and number tokens
+	ctx          context.Context   // cancels parsing when set, see ParseContext
+	tokens       int               // number of tokens read, used to pace context checks
+	keys         map[string]string // keys seen so far, when InternKeys is set
+	comments     []comment         // comments not attached to a value yet, when KeepComments is set
 }
 
+// utf8BOM is the byte order mark some editors put at the start of UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
 // NewJSONParser creates a new JSONParser instance
 func NewJSONParser(input []byte) *JSONParser {
-	return &JSONParser{input: bytes.NewBuffer(input), currentToken: ""}
+	p := &JSONParser{input: bytes.NewBuffer(input), source: input, currentToken: ""}
+	p.skipBOM()
+
+	return p
+}
+
+// NewJSONParserFromReader creates a new JSONParser instance reading its input from r.
+// The reader is consumed when parsing starts.
+func NewJSONParserFromReader(r io.Reader) *JSONParser {
+	return &JSONParser{input: &bytes.Buffer{}, reader: r, currentToken: ""}
+}
+
+// Reset makes the parser read a new input, so one parser can be reused, for
+// example from a sync.Pool, without allocating a new one. Options are kept.
+func (p *JSONParser) Reset(input []byte) {
+	if p.input == nil {
+		p.input = &bytes.Buffer{}
+	}
+	*p.input = *bytes.NewBuffer(input)
+	p.source = input
+	p.reader = nil
+	p.window = nil
+	p.currentToken = ""
+	p.currentKind = tokenDelim
+	p.offset = 0
+	p.tokenOffset = 0
+	p.depth = 0
+	p.discard = false
+	p.dropElements()
+	p.ctx = nil
+	p.tokens = 0
+	clear(p.keys)
+	p.comments = p.comments[:0]
+	p.skipBOM()
+}
+
+// readInput loads any pending reader input into the buffer and enforces MaxInputBytes
+func (p *JSONParser) readInput() error {
+	if p.reader != nil {
+		r := p.reader
+		p.reader = nil
+		if p.MaxInputBytes > 0 {
+			// Read one byte past the limit so that oversized input can be told apart
+			r = io.LimitReader(r, int64(p.MaxInputBytes)+1)
+		}
+		if _, err := p.input.ReadFrom(r); err != nil {
+			return fmt.Errorf("reading input: %w", err)
+		}
+		p.source = p.input.Bytes()
+		p.skipBOM()
+	}
+
+	size := int64(len(p.source))
+	if p.window != nil {
+		size = p.window.size
+	}
+	if p.MaxInputBytes > 0 && size > int64(p.MaxInputBytes) {
+		return fmt.Errorf("input exceeds the maximum size of %d bytes", p.MaxInputBytes)
+	}
+
+	return nil
+}
+
+// skipBOM skips a leading byte order mark, offsets still count it.
+// Only input that starts like one is buffered further, so a short stream is not waited on.
+func (p *JSONParser) skipBOM() {
+	if p.offset == 0 && p.buffered(1) > 0 && p.input.Bytes()[0] == utf8BOM[0] &&
+		p.buffered(len(utf8BOM)) >= len(utf8BOM) && bytes.HasPrefix(p.input.Bytes(), utf8BOM) {
+		p.next(len(utf8BOM))
+	}
+}
+
+// Parse parses a single JSON value from the input, rejecting anything left over after it
+func Parse(input []byte) (JSONValue, error) {
+	return NewJSONParser(input).Parse()
+}
+
+// ParseString is like Parse but takes the input as a string
+func ParseString(s string) (JSONValue, error) {
+	// The parser only ever reads its input and copies every token it keeps,
+	// so the string's bytes can be used directly instead of copying them
+	return Parse(unsafe.Slice(unsafe.StringData(s), len(s)))
+}
+
+// ParseObject parses a JSON document whose root must be an object and returns its fields
+func ParseObject(data []byte) (map[string]JSONValue, error) {
+	value, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if value.Kind != KindObject {
+		return nil, typeError(KindObject, value)
+	}
+
+	return value.Value.(map[string]JSONValue), nil
+}
+
+// ParseArray parses a JSON document whose root must be an array and returns its elements
+func ParseArray(data []byte) ([]JSONValue, error) {
+	value, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if value.Kind != KindArray {
+		return nil, typeError(KindArray, value)
+	}
+
+	return value.Value.([]JSONValue), nil
+}
+
+// Valid reports whether data is a single valid JSON value, without building the parsed tree.
+// Whitespace around the value, such as a final newline, is allowed.
+func Valid(data []byte) bool {
+	parser := NewJSONParser(data)
+	parser.discard = true
+	_, err := parser.Parse()
+
+	return err == nil
+}
+
+// Parse parses a single JSON value from the parser's input, rejecting anything left over after it
+func (p *JSONParser) Parse() (JSONValue, error) {
+	if err := p.startDocument(); err != nil {
+		return JSONValue{}, err
+	}
+
+	value, err := p.parseRoot()
+	if err != nil {
+		return JSONValue{}, err
+	}
+	if err := p.endDocument(); err != nil {
+		return JSONValue{}, err
+	}
+	if after := p.takeComments(); after != nil {
+		value.ensureComments().After = after
+	}
+
+	return value, nil
+}
+
+// startDocument reads the input and checks that it holds a root value
+func (p *JSONParser) startDocument() error {
+	if err := p.readInput(); err != nil {
+		return err
+	}
+
+	// An empty or whitespace-only document has no root value
+	if err := p.skipWhitespaces(); err != nil {
+		return err
+	}
+	if p.buffered(1) == 0 {
+		return p.errorAt(p.offset, "unexpected end of input, document is empty")
+	}
+
+	return nil
+}
+
+// endDocument checks that only whitespace follows the root value
+func (p *JSONParser) endDocument() error {
+	if err := p.skipWhitespaces(); err != nil {
+		return err
+	}
+	if p.buffered(1) > 0 {
+		return p.errorAt(p.offset, "unexpected trailing data")
+	}
+	if p.window != nil && p.window.err != nil {
+		return fmt.Errorf("reading input: %w", p.window.err)
+	}
+
+	return nil
+}
+
+// buffered returns the number of input bytes available to next, first
+// refilling the buffer from a window to hold at least n bytes if it can.
+// Refilling may move the buffered bytes, so slices returned by next must
+// not be kept across calls.
+func (p *JSONParser) buffered(n int) int {
+	if p.input.Len() < n && p.window != nil {
+		p.window.fill(p.input, n)
+	}
+
+	return p.input.Len()
+}
+
+// next consumes n bytes from the input, keeping the offset in step
+func (p *JSONParser) next(n int) []byte {
+	consumed := p.input.Next(n)
+	p.offset += len(consumed)
+	return consumed
+}
+
+// peek returns the next input byte without consuming it, and false at the end of the input
+func (p *JSONParser) peek() (byte, bool) {
+	if p.buffered(1) == 0 {
+		return 0, false
+	}
+
+	return p.input.Bytes()[0], true
+}
+
+// errorAt builds a SyntaxError for the byte offset it refers to, with its line and column
+func (p *JSONParser) errorAt(offset int, format string, args ...interface{}) error {
+	// Input cut short by a failed read is reported as that failure
+	if p.window != nil && p.window.err != nil {
+		return fmt.Errorf("reading input: %w", p.window.err)
+	}
+
+	line, column := p.position(offset)
+	return &SyntaxError{Msg: fmt.Sprintf(format, args...), Offset: offset, Line: line, Column: column}
+}
+
+// unexpectedToken reports that the current token is not the expected one
+func (p *JSONParser) unexpectedToken(expected string) error {
+	return p.errorAt(p.tokenOffset, "expected %s but found %s", expected, p.describeToken())
+}
+
+// describeToken renders the current token for error messages, telling
+// delimiters apart from strings that contain the same text
+func (p *JSONParser) describeToken() string {
+	if p.discard && (p.currentKind == tokenString || p.currentKind == tokenNumber) && p.offset <= len(p.source) {
+		// Checking syntax keeps no token text, so show the token as written
+		return string(p.source[p.tokenOffset:p.offset])
+	}
+
+	switch p.currentKind {
+	case tokenDelim:
+		return "'" + p.currentToken + "'"
+	case tokenString:
+		return strconv.Quote(p.currentToken)
+	default:
+		return p.currentToken
+	}
+}
+
+// position converts a byte offset into a 1-based line and column
+func (p *JSONParser) position(offset int) (line, column int) {
+	if p.window != nil {
+		return p.window.position(offset)
+	}
+	if offset > len(p.source) {
+		offset = len(p.source)
+	}
+	consumed := p.source[:offset]
+	line = 1 + bytes.Count(consumed, []byte{'\n'})
+	column = offset - bytes.LastIndexByte(consumed, '\n')
+
+	return line, column
 }
 
 // readNextToken reads the next JSON token from the input
-func (p *JSONParser) readNextToken() bool {
-	p.skipWhitespaces()
+func (p *JSONParser) readNextToken() error {
+	if err := p.checkContext(); err != nil {
+		return err
+	}
+	if err := p.skipWhitespaces(); err != nil {
+		return err
+	}
 
-	if p.input.Len() == 0 {
-		return false
+	if p.buffered(1) == 0 {
+		return p.errorAt(p.offset, "unexpected end of input")
 	}
+	p.tokenOffset = p.offset
 
 	// Read the next character
-	currentChar := p.input.Next(1)
+	currentChar := p.next(1)[0]
+
+	if p.AllowUnquotedKeys && isIdentifierStart(currentChar) {
+		return p.readIdentifier(currentChar)
+	}
 
 	// Check the type of the token
-	switch currentChar[0] {
+	switch currentChar {
 	case '{', '}', '[', ']', ':', ',':
-		p.currentToken = string(currentChar)
+		// Slicing a constant keeps the single byte token from allocating
+		i := strings.IndexByte(delimiters, currentChar)
+		p.currentToken = delimiters[i : i+1]
+		p.currentKind = tokenDelim
 	case 'n': // Check for null
-		if p.input.Len() >= 4 && string(p.input.Next(4)) == "null"  {
+		if p.buffered(3) >= 3 && string(p.next(3)) == "ull" {
 			p.currentToken = "null"
+			p.currentKind = tokenLiteral
 		} else {
-			return false
+			return p.errorAt(p.tokenOffset, "invalid literal, expected %q", "null")
 		}
 	case 't': // Check for true
-		if p.input.Len() >= 4 && string(p.input.Next(4)) == "true" {
+		if p.buffered(3) >= 3 && string(p.next(3)) == "rue" {
 			p.currentToken = "true"
+			p.currentKind = tokenLiteral
 		} else {
-			return false
+			return p.errorAt(p.tokenOffset, "invalid literal, expected %q", "true")
 		}
 	case 'f': // Check for false
-		if p.input.Len() >= 5 && string(p.input.Next(5)) == "false" {
+		if p.buffered(4) >= 4 && string(p.next(4)) == "alse" {
 			p.currentToken = "false"
+			p.currentKind = tokenLiteral
 		} else {
-			return false
+			return p.errorAt(p.tokenOffset, "invalid literal, expected %q", "false")
 		}
-	case '"': // Check for string
-		start := p.input.Len()
-		for p.input.Len() > 0 {
-			currentChar := p.input.Next(1)
-			if currentChar[0] == '"' {
-				p.currentToken = p.input.String()[start:p.input.Len()-1]
-				break
-			}
+	case '"', '\'': // Check for string
+		if currentChar == '\'' && !p.AllowSingleQuotes {
+			return p.errorAt(p.tokenOffset, "unexpected character %q, single quoted strings are not allowed", currentChar)
 		}
+		token, err := p.readString(currentChar)
+		if err != nil {
+			return err
+		}
+		p.currentToken = token
+		p.currentKind = tokenString
+	case 'N', 'I': // Check for NaN and Infinity
+		if !p.AllowNonFiniteNumbers {
+			return p.errorAt(p.tokenOffset, "unexpected character %q", currentChar)
+		}
+		word := "NaN"
+		if currentChar == 'I' {
+			word = "Infinity"
+		}
+		return p.readNonFinite(word)
 	default: // Check for number
-		start := p.input.Len()
-		for p.input.Len() > 0 {
-			currentChar := p.input.Next(1)
-			if bytes.IndexByte([]byte("0123456789+-.eE"), currentChar[0]) < 0 {
-				p.input.UnreadByte() // Unread the non-numeric character
-				p.currentToken = p.input.String()[start:p.input.Len()]
+		if next, ok := p.peek(); currentChar == '-' && p.AllowNonFiniteNumbers && ok && next == 'I' {
+			return p.readNonFinite("-Infinity")
+		}
+		if currentChar == '+' {
+			// A plus sign is only allowed in exponents such as 1e+5
+			return p.errorAt(p.tokenOffset, "invalid number, leading '+' is not allowed")
+		}
+		if currentChar != '-' && (currentChar < '0' || currentChar > '9') {
+			return p.errorAt(p.tokenOffset, "unexpected character %q", currentChar)
+		}
+		// Collect the number in the scratch buffer so only the final token is allocated
+		token := append(p.scratch[:0], currentChar)
+		defer func() { p.scratch = token }()
+		for {
+			// Stop in front of the first byte that cannot be part of a number
+			nextChar, ok := p.peek()
+			if !ok || strings.IndexByte("0123456789+-.eE", nextChar) < 0 {
 				break
 			}
+			token = append(token, nextChar)
+			p.next(1)
+		}
+		if !isValidNumber(token) {
+			if hasLeadingZero(token) {
+				return p.errorAt(p.tokenOffset, "invalid number %q, leading zeros are not allowed", token)
+			}
+			return p.errorAt(p.tokenOffset, "invalid number %q", token)
+		}
+		p.currentToken = ""
+		if !p.discard {
+			p.currentToken = string(token)
 		}
+		p.currentKind = tokenNumber
 	}
 
-	return true
+	return nil
 }
 
-// skipWhitespaces skips whitespaces in the input buffer
-func (p *JSONParser) skipWhitespaces() {
-	for p.input.Len() > 0 {
-		currentChar := p.input.Next(1)
-		if currentChar[0] != ' ' && currentChar[0] != '\n' && currentChar[0] != '\r' && currentChar[0] != '\t' {
-			p.input.UnreadByte() // Unread the non-whitespace character
+// readNonFinite reads the rest of a NaN, Infinity or -Infinity token after its first byte
+func (p *JSONParser) readNonFinite(word string) error {
+	rest := word[1:]
+	if p.buffered(len(rest)) < len(rest) || string(p.next(len(rest))) != rest {
+		return p.errorAt(p.tokenOffset, "invalid literal, expected %q", word)
+	}
+	p.currentToken = word
+	p.currentKind = tokenNumber
+
+	return nil
+}
+
+// readIdentifier reads a bare word starting with first, recognizing the
+// literals and, if allowed, the non-finite numbers among them
+func (p *JSONParser) readIdentifier(first byte) error {
+	word := append(p.scratch[:0], first)
+	defer func() { p.scratch = word }()
+	for {
+		nextChar, ok := p.peek()
+		if !ok || !isIdentifierStart(nextChar) && (nextChar < '0' || nextChar > '9') {
 			break
 		}
+		word = append(word, nextChar)
+		p.next(1)
 	}
+
+	p.currentToken = string(word)
+	p.currentKind = tokenIdentifier
+	switch p.currentToken {
+	case "null", "true", "false":
+		p.currentKind = tokenLiteral
+	case "NaN", "Infinity":
+		if p.AllowNonFiniteNumbers {
+			p.currentKind = tokenNumber
+		}
+	}
+
+	return nil
+}
+
+// isIdentifierStart reports whether c may start an unquoted key
+func isIdentifierStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+}
+
+// isValidNumber reports whether the token matches the JSON number grammar:
+// an optional minus, an integer part without leading zeros, an optional
+// fraction and an optional exponent
+func isValidNumber(token []byte) bool {
+	i := 0
+	if i < len(token) && token[i] == '-' {
+		i++
+	}
+
+	// Integer part
+	switch {
+	case i < len(token) && token[i] == '0':
+		i++
+	case i < len(token) && token[i] >= '1' && token[i] <= '9':
+		for i < len(token) && token[i] >= '0' && token[i] <= '9' {
+			i++
+		}
+	default:
+		return false
+	}
+
+	// Fraction
+	if i < len(token) && token[i] == '.' {
+		i++
+		start := i
+		for i < len(token) && token[i] >= '0' && token[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+
+	// Exponent
+	if i < len(token) && (token[i] == 'e' || token[i] == 'E') {
+		i++
+		if i < len(token) && (token[i] == '+' || token[i] == '-') {
+			i++
+		}
+		start := i
+		for i < len(token) && token[i] >= '0' && token[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+
+	return i == len(token)
 }
 
-// parseValue parses a JSON value
-func (p *JSONParser) parseValue() JSONValue {
-	p.readNextToken()
+// hasLeadingZero reports whether the integer part of a number token is a zero followed by more digits
+func hasLeadingZero(token []byte) bool {
+	token = bytes.TrimPrefix(token, []byte("-"))
+
+	return len(token) > 1 && token[0] == '0' && token[1] >= '0' && token[1] <= '9'
+}
+
+// readString reads the rest of a string opened by the quote character, decoding escape sequences
+func (p *JSONParser) readString(quote byte) (string, error) {
+	// Most strings are already buffered in full and have nothing to decode,
+	// so they can be copied out directly
+	rest := p.input.Bytes()
+	if end := plainLength(rest, quote); end < len(rest) && rest[end] == quote &&
+		(p.MaxStringLength == 0 || end <= p.MaxStringLength) && (!p.ValidateUTF8 || utf8.Valid(rest[:end])) {
+		token := p.tokenString(rest[:end])
+		p.next(end + 1)
+		return token, nil
+	}
+
+	// Decode into the reusable scratch buffer, keeping any growth for the next string
+	token := p.scratch[:0]
+	defer func() { p.scratch = token }()
+
+	for p.buffered(1) > 0 {
+		if p.MaxStringLength > 0 && len(token) > p.MaxStringLength {
+			return "", p.errorAt(p.tokenOffset, "string exceeds the maximum length of %d bytes", p.MaxStringLength)
+		}
+		if c, _ := p.peek(); c >= utf8.RuneSelf && p.ValidateUTF8 {
+			// Take multi-byte characters whole so malformed ones can be caught
+			p.buffered(utf8.UTFMax)
+			r, size := utf8.DecodeRune(p.input.Bytes())
+			if r == utf8.RuneError && size == 1 {
+				return "", p.errorAt(p.offset, "invalid UTF-8 byte %#x in string", c)
+			}
+			token = append(token, p.next(size)...)
+			continue
+		}
+
+		currentChar := p.next(1)[0]
+		if currentChar == quote {
+			return p.tokenString(token), nil
+		}
+		if currentChar < 0x20 && !p.AllowControlCharacters {
+			return "", p.errorAt(p.offset-1, "invalid control character %q in string", currentChar)
+		}
+		if currentChar != '\\' || p.buffered(1) == 0 {
+			token = append(token, currentChar)
+			continue
+		}
+
+		// Decode the escape sequence
+		escapeOffset := p.offset - 1
+		escaped := p.next(1)
+		switch escaped[0] {
+		case '"', '\\', '/':
+			token = append(token, escaped[0])
+		case '\'':
+			if quote != '\'' {
+				return "", p.errorAt(escapeOffset, "invalid escape sequence %q", "\\'")
+			}
+			token = append(token, '\'')
+		case 'b':
+			token = append(token, '\b')
+		case 'f':
+			token = append(token, '\f')
+		case 'n':
+			token = append(token, '\n')
+		case 'r':
+			token = append(token, '\r')
+		case 't':
+			token = append(token, '\t')
+		case 'u':
+			r, err := p.readHexRune(escapeOffset)
+			if err != nil {
+				return "", err
+			}
+			if utf16.IsSurrogate(r) {
+				if r, err = p.readSurrogatePair(escapeOffset, r); err != nil {
+					return "", err
+				}
+			}
+			// Any code point is kept as is, including \u0000, since Go strings may hold NUL bytes
+			token = utf8.AppendRune(token, r)
+		default:
+			return "", p.errorAt(escapeOffset, "invalid escape sequence %q", "\\"+string(escaped))
+		}
+	}
+
+	// The input ran out before the closing quote
+	return "", p.errorAt(p.tokenOffset, "unterminated string")
+}
+
+// plainLength returns the length of the leading run of bytes that a string can
+// hold as is, stopping at the closing quote, a backslash or a control character
+func plainLength(data []byte, quote byte) int {
+	for i, c := range data {
+		if c == quote || c == '\\' || c < 0x20 {
+			return i
+		}
+	}
+
+	return len(data)
+}
+
+// readHexRune reads the four hex digits of a \uXXXX escape starting at escapeOffset
+func (p *JSONParser) readHexRune(escapeOffset int) (rune, error) {
+	if p.buffered(4) < 4 {
+		return 0, p.errorAt(escapeOffset, "incomplete unicode escape")
+	}
+	digits := string(p.next(4))
+	code, err := strconv.ParseUint(digits, 16, 16)
+	if err != nil {
+		return 0, p.errorAt(escapeOffset, "invalid unicode escape %q", "\\u"+digits)
+	}
+
+	return rune(code), nil
+}
+
+// readSurrogatePair combines a high surrogate with the \uXXXX low surrogate that must follow it
+func (p *JSONParser) readSurrogatePair(escapeOffset int, high rune) (rune, error) {
+	if high >= 0xDC00 {
+		return 0, p.errorAt(escapeOffset, "unexpected low surrogate \\u%04X", high)
+	}
+	if p.buffered(2) < 2 || string(p.next(2)) != "\\u" {
+		return 0, p.errorAt(escapeOffset, "lone high surrogate \\u%04X", high)
+	}
+	low, err := p.readHexRune(p.offset - 2)
+	if err != nil {
+		return 0, err
+	}
+	r := utf16.DecodeRune(high, low)
+	if r == utf8.RuneError {
+		return 0, p.errorAt(escapeOffset, "invalid surrogate pair \\u%04X\\u%04X", high, low)
+	}
+
+	return r, nil
+}
+
+// skipWhitespaces skips whitespaces in the input buffer, and comments when they are allowed
+func (p *JSONParser) skipWhitespaces() error {
+	newline := false
+	for {
+		c, ok := p.peek()
+		switch {
+		case ok && c == '/' && p.AllowComments:
+			p.next(1)
+			text, err := p.readComment()
+			if err != nil {
+				return err
+			}
+			if p.KeepComments {
+				p.comments = append(p.comments, comment{text: string(text), ownLine: newline})
+			}
+			// A line comment ends with the newline after it
+			newline = newline || text[1] == '/'
+		case ok && p.isWhitespace(c):
+			newline = newline || c == '\n'
+			p.next(1)
+		default:
+			return nil
+		}
+	}
+}
+
+// isWhitespace reports whether c is insignificant whitespace between tokens
+func (p *JSONParser) isWhitespace(c byte) bool {
+	switch c {
+	case ' ', '\n', '\r', '\t':
+		return true
+	}
+
+	return p.ExtraWhitespace != "" && strings.IndexByte(p.ExtraWhitespace, c) >= 0
+}
+
+// readComment reads the rest of a comment whose leading '/' has already been read.
+// It returns the comment's text without the newline ending a line comment, in
+// the scratch buffer so it is only valid until the next token is read.
+func (p *JSONParser) readComment() ([]byte, error) {
+	start := p.offset - 1
+	if next, ok := p.peek(); !ok || (next != '/' && next != '*') {
+		return nil, p.errorAt(start, "unexpected character %q", '/')
+	}
+
+	text := append(p.scratch[:0], '/')
+	defer func() { p.scratch = text }()
+
+	text = append(text, p.next(1)[0])
+	if text[1] == '/' {
+		// Line comments run up to and including the next newline
+		for p.buffered(1) > 0 {
+			rest := p.input.Bytes()
+			if end := bytes.IndexByte(rest, '\n'); end >= 0 {
+				text = append(text, p.next(end + 1)[:end]...)
+				break
+			}
+			text = append(text, p.next(len(rest))...)
+		}
+		return bytes.TrimSuffix(text, []byte("\r")), nil
+	}
+
+	for p.buffered(2) >= 2 {
+		rest := p.input.Bytes()
+		if end := bytes.Index(rest, []byte("*/")); end >= 0 {
+			text = append(text, p.next(end+2)...)
+			return text, nil
+		}
+		// Keep the last byte, it may be the '*' of a "*/" that is not buffered yet
+		text = append(text, p.next(len(rest)-1)...)
+	}
+
+	return nil, p.errorAt(start, "unterminated comment")
+}
+
+// parseRoot parses a top level value with the configured parsing strategy
+func (p *JSONParser) parseRoot() (JSONValue, error) {
+	// Drop anything a failed parse left on the scratch stack
+	defer p.dropElements()
+
+	if p.Iterative {
+		return p.parseIterative()
+	}
+
+	return p.parseValue()
+}
+
+// parseValue reads the next token and parses the JSON value it starts
+func (p *JSONParser) parseValue() (JSONValue, error) {
+	if err := p.readNextToken(); err != nil {
+		return JSONValue{}, err
+	}
+
+	return p.parseToken()
+}
+
+// parseToken parses the JSON value starting at the current token
+func (p *JSONParser) parseToken() (JSONValue, error) {
+	start := p.tokenOffset
+	// Comments read so far precede this value, arrays and objects read more of their own
+	before := p.takeComments()
+	value, err := p.parseTokenValue()
+	if err != nil {
+		return JSONValue{}, err
+	}
+	if before != nil {
+		value.ensureComments().Before = before
+	}
+
+	return p.span(value, start), nil
+}
+
+// span records that a value occupies the source from start up to the current offset
+func (p *JSONParser) span(value JSONValue, start int) JSONValue {
+	if p.RecordOffsets {
+		value.Start, value.End = start, p.offset
+	}
+
+	return value
+}
+
+// parseTokenValue builds the value starting at the current token for parseToken
+func (p *JSONParser) parseTokenValue() (JSONValue, error) {
+	switch p.currentKind {
+	case tokenString:
+		return JSONValue{Kind: KindString, Value: p.currentToken}, nil
+	case tokenNumber:
+		if p.discard {
+			return JSONValue{Kind: KindNumber}, nil
+		}
+		if p.UseNumber {
+			return JSONValue{Kind: KindNumber, Value: Number(p.currentToken)}, nil
+		}
+
+		// Convert the number to float64. Numbers too large for it are rejected rather
+		// than turned into infinities, Valid only checks syntax above and accepts them.
+		num, err := Number(p.currentToken).Float64()
+		if err != nil {
+			return JSONValue{}, p.errorAt(p.tokenOffset, "number %s overflows float64, UseNumber keeps its text", p.currentToken)
+		}
+		return JSONValue{Kind: KindNumber, Value: num}, nil
+	}
 
 	switch p.currentToken {
-	case "null", "true", "false":
-		return JSONValue{Type: p.currentToken, Value: nil}
+	case "null":
+		return JSONValue{Kind: KindNull, Value: nil}, nil
+	case "true", "false":
+		return JSONValue{Kind: KindBool, Value: p.currentToken == "true"}, nil
 	case "{":
 		return p.parseObject()
 	case "[":
 		return p.parseArray()
 	default:
-		// Check if the token is a number
-		if _, err := json.Number(p.currentToken).Float64(); err == nil {
-			// Convert the number to float64
-			num, _ := json.Number(p.currentToken).Float64()
-			return JSONValue{Type: "number", Value: num}
-		}
+		return JSONValue{}, p.unexpectedToken("value")
+	}
+}
 
-		// Otherwise, it must be a string
-		return JSONValue{Type: "string", Value: p.currentToken}
+// enterContainer records that an array or object was opened, enforcing MaxDepth
+func (p *JSONParser) enterContainer() error {
+	maxDepth := p.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
 	}
+	p.depth++
+	if p.depth > maxDepth {
+		return p.errorAt(p.tokenOffset, "exceeded maximum nesting depth of %d", maxDepth)
+	}
+
+	return nil
 }
 
 // parseObject parses a JSON object
-func (p *JSONParser) parseObject() JSONValue {
-	object := make(map[string]JSONValue)
+func (p *JSONParser) parseObject() (JSONValue, error) {
+	if err := p.enterContainer(); err != nil {
+		return JSONValue{}, err
+	}
+	defer func() { p.depth-- }()
 
-	p.readNextToken()
-	for p.currentToken != "}" {
-		key := p.currentToken
+	var object map[string]JSONValue
+	var keyOrder []string
+	if !p.discard {
+		object = make(map[string]JSONValue)
+	}
 
-		// Read the ':' separator
-		p.readNextToken()
-		if p.currentToken != ":" {
-			panic("Expected ':'")
+	if err := p.readNextToken(); err != nil {
+		return JSONValue{}, err
+	}
+	for !p.atDelim("}") {
+		key, keyOffset, err := p.readKey()
+		if err != nil {
+			return JSONValue{}, err
+		}
+
+		// Parse the value and add it to the object, parseValue reads its own first token
+		value, err := p.parseValue()
+		if err != nil {
+			return JSONValue{}, err
+		}
+		if err := p.storeField(object, &keyOrder, key, keyOffset, value); err != nil {
+			return JSONValue{}, err
+		}
+		if err := p.readSeparator("}"); err != nil {
+			return JSONValue{}, err
 		}
+		p.attachAfterComments(object, key)
+	}
+
+	return p.attachEndComments(JSONValue{Kind: KindObject, Value: object, KeyOrder: keyOrder}, nil), nil
+}
+
+// readKey takes the current token as an object key and reads the ':' separator after it
+func (p *JSONParser) readKey() (string, int, error) {
+	// With AllowUnquotedKeys any bare word is a key, including literals such as true
+	bareWord := p.AllowUnquotedKeys && p.currentKind != tokenString && p.currentKind != tokenDelim &&
+		p.currentToken != "" && isIdentifierStart(p.currentToken[0])
+	if p.currentKind != tokenString && !bareWord {
+		return "", 0, p.unexpectedToken("string key")
+	}
+	key := p.currentToken
+	keyOffset := p.tokenOffset
+	if p.InternKeys && !p.discard {
+		key = p.internKey(key)
+	}
 
-		// Parse the value and add it to the object
-		p.readNextToken()
-		value := p.parseValue()
-		object[key] = value
+	// Read the ':' separator
+	if err := p.readNextToken(); err != nil {
+		return "", 0, err
+	}
+	if p.currentToken != ":" {
+		return "", 0, p.unexpectedToken("':'")
+	}
 
-		// Read the next token (',' or '}')
-		p.readNextToken()
+	return key, keyOffset, nil
+}
+
+// tokenString copies the decoded bytes of a string token, unless syntax is only
+// being checked. With InternKeys a string equal to a key seen before reuses that
+// key instead of being allocated again.
+func (p *JSONParser) tokenString(b []byte) string {
+	if p.discard {
+		// Only the syntax is checked, the text is recovered from the input for errors
+		return ""
+	}
+	if p.InternKeys {
+		if interned, ok := p.keys[string(b)]; ok {
+			return interned
+		}
 	}
 
-	return JSONValue{Type: "object", Value: object}
+	return string(b)
+}
+
+// internKey returns the first copy of key seen by the parser
+func (p *JSONParser) internKey(key string) string {
+	if interned, ok := p.keys[key]; ok {
+		return interned
+	}
+	if p.keys == nil {
+		p.keys = make(map[string]string)
+	}
+	p.keys[key] = key
+
+	return key
+}
+
+// storeField adds a parsed field to an object under construction, tracking key order
+func (p *JSONParser) storeField(object map[string]JSONValue, keyOrder *[]string, key string, keyOffset int, value JSONValue) error {
+	if p.discard {
+		return nil
+	}
+	if _, exists := object[key]; !exists {
+		*keyOrder = append(*keyOrder, key)
+	} else if p.DisallowDuplicateKeys {
+		return p.errorAt(keyOffset, "duplicate key %q", key)
+	}
+	object[key] = value
+
+	return nil
 }
 
 // parseArray parses a JSON array
-func (p *JSONParser) parseArray() JSONValue {
-	array := make([]JSONValue, 0)
+func (p *JSONParser) parseArray() (JSONValue, error) {
+	if err := p.enterContainer(); err != nil {
+		return JSONValue{}, err
+	}
+	defer func() { p.depth-- }()
+
+	// Elements are collected on the shared scratch stack, which is reused
+	// across arrays instead of growing a new slice for each of them
+	p.reserveElements()
+	start := len(p.elements)
 
-	p.readNextToken()
-	for p.currentToken != "}" {
+	if err := p.readNextToken(); err != nil {
+		return JSONValue{}, err
+	}
+	for !p.atDelim("]") {
 		// Parse the value and add it to the array
-		value := p.parseValue()
-		array = append(array, value)
+		value, err := p.parseToken()
+		if err != nil {
+			return JSONValue{}, err
+		}
+		if !p.discard {
+			p.elements = append(p.elements, value)
+		}
+
+		if err := p.readSeparator("]"); err != nil {
+			return JSONValue{}, err
+		}
+		p.attachAfterComments(nil, "")
+	}
+	if p.discard {
+		// Storing even an empty slice in Value would allocate
+		return JSONValue{Kind: KindArray}, nil
+	}
 
-		// Read the next token (',' or ']')
-		p.readNextToken()
+	return p.attachEndComments(JSONValue{Kind: KindArray, Value: p.takeElements(start)}, nil), nil
+}
+
+// bytesPerElement is the input size assumed per array element when reserving the scratch stack
+const bytesPerElement = 4
+
+// maxReservedElements bounds the scratch stack reserved up front, so that a large
+// input holding only small arrays does not pay for a large one
+const maxReservedElements = 1 << 14
+
+// reserveElements gives an empty scratch stack room for the elements the rest of
+// the input could hold, so that a large array does not grow it one doubling at a time
+func (p *JSONParser) reserveElements() {
+	if cap(p.elements) > 0 || p.discard {
+		return
 	}
+	p.elements = make([]JSONValue, 0, min(p.input.Len()/bytesPerElement, maxReservedElements))
+}
+
+// takeElements moves the elements collected since start off the scratch stack into a new slice
+func (p *JSONParser) takeElements(start int) []JSONValue {
+	n := len(p.elements) - start
+
+	// With no enclosing array on the stack the scratch slice can be handed over when
+	// the array fills most of it. It is clipped to its length so that appending to
+	// the array, or to a copy of it, never writes into shared spare capacity.
+	if start == 0 && n > 0 && n >= cap(p.elements)/2 {
+		array := p.elements[:n:n]
+		p.elements = nil
+		return array
+	}
+
+	array := make([]JSONValue, n)
+	copy(array, p.elements[start:])
 
-	return JSONValue{Type: "array", Value: array}
+	// Clear the popped entries so the scratch stack does not keep them alive
+	clear(p.elements[start:])
+	p.elements = p.elements[:start]
+
+	return array
 }
 
-func main() {
-	// Example JSON data
-	jsonData := []byte(`{
-		"name": "John Doe",
-		"age": 30,
-		"email": "john.doe@example.com",
-		"active": true,
-		"address": {
-			"city": "New York",
-			"zip": "10001"
-		},
-		"tags": ["golang", "json", "parser", [1, 2, 3]]
-	}`)
+// dropElements empties the scratch stack, so that elements left over from a failed
+// parse do not stay reachable
+func (p *JSONParser) dropElements() {
+	clear(p.elements)
+	p.elements = p.elements[:0]
+}
 
-	// Create a new JSONParser instance
-	parser := NewJSONParser(jsonData)
+// atDelim reports whether the current token is the given delimiter, rather than
+// a string with the same text
+func (p *JSONParser) atDelim(delim string) bool {
+	return p.currentKind == tokenDelim && p.currentToken == delim
+}
 
-	// Parse the JSON data
-	result := parser.parseValue()
+// readSeparator reads the token after an element, which must be a ',' or the
+// closing token, and moves past a ',' to the start of the next element. That
+// may only be the closing token when trailing commas are allowed.
+func (p *JSONParser) readSeparator(closing string) error {
+	if err := p.readNextToken(); err != nil {
+		return err
+	}
+	if p.atDelim(closing) {
+		return nil
+	}
+	if !p.atDelim(",") {
+		return p.unexpectedToken("',' or '" + closing + "'")
+	}
+
+	if err := p.readNextToken(); err != nil {
+		return err
+	}
+	if p.atDelim(closing) && !p.AllowTrailingCommas {
+		return p.errorAt(p.tokenOffset, "trailing comma before '%s'", closing)
+	}
 
-	// Print the parsed JSON data
-	fmt.Printf("%+v\n", result)
+	return nil
 }
@@ -0,0 +1,1107 @@
+package jsonparser
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// sampleDocument is the document parsed by the example program
+const sampleDocument = `{
+	"name": "John Doe",
+	"age": 30,
+	"email": "john.doe@example.com",
+	"active": true,
+	"address": {
+		"city": "New York",
+		"zip": "10001"
+	},
+	"tags": ["golang", "json", "parser", [1, 2, 3]]
+}`
+
+// mustParse parses input and fails the test on error
+func mustParse(t *testing.T, input string) JSONValue {
+	t.Helper()
+	value, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+
+	return value
+}
+
+// parseError parses input, which must be invalid, and returns the error text
+func parseError(t *testing.T, input string) string {
+	t.Helper()
+	if _, err := Parse([]byte(input)); err != nil {
+		return err.Error()
+	}
+	t.Fatalf("Parse(%q) succeeded, want an error", input)
+
+	return ""
+}
+
+func TestParseArrays(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"flat", `[1, 2, 3]`, `[1,2,3]`},
+		{"nested", `[[1, [2, 3]], [[]], [4]]`, `[[1,[2,3]],[[]],[4]]`},
+		{"empty", `[]`, `[]`},
+		{"empty with whitespace", "[ \n ]", `[]`},
+		{"end of object", `{"a": 1, "list": [1, [2]]}`, `{"a":1,"list":[1,[2]]}`},
+		{"empty at end of object", `{"a": {"b": []}}`, `{"a":{"b":[]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustParse(t, tt.input).String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStringEscapes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"he said \"hi\""`, `he said "hi"`},
+		{`"a\\b"`, `a\b`},
+		{`"a\/b"`, `a/b`},
+		{`"a\bb"`, "a\bb"},
+		{`"a\fb"`, "a\fb"},
+		{`"a\nb"`, "a\nb"},
+		{`"a\rb"`, "a\rb"},
+		{`"a\tb"`, "a\tb"},
+		{`"ends with a backslash\\"`, `ends with a backslash\`},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.input).Value; got != tt.want {
+			t.Errorf("Parse(%s) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnicodeEscapes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"\u20ac"`, "\u20ac"},
+		{`"price: \u20AC5"`, "price: \u20ac5"},
+		{`"\u00e9t\u00e9"`, "\u00e9t\u00e9"},
+		{`"\u0041"`, "A"},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.input).Value; got != tt.want {
+			t.Errorf("Parse(%s) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	errorTests := []struct {
+		input string
+		want  string
+	}{
+		{`"\u20g0"`, `invalid unicode escape "\\u20g0" at offset 1 (line 1, column 2)`},
+		{`"\u12"`, `incomplete unicode escape at offset 1 (line 1, column 2)`},
+	}
+	for _, tt := range errorTests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseSurrogatePairs(t *testing.T) {
+	if got := mustParse(t, `"\uD83D\uDE00"`).Value; got != "\U0001F600" {
+		t.Errorf("got %q, want %q", got, "\U0001F600")
+	}
+	if got := mustParse(t, `"hi \ud83d\ude00!"`).Value; got != "hi \U0001F600!" {
+		t.Errorf("got %q, want %q", got, "hi \U0001F600!")
+	}
+
+	errorTests := []struct {
+		input string
+		want  string
+	}{
+		{`"\uD83D"`, `lone high surrogate \uD83D at offset 1 (line 1, column 2)`},
+		{`"\uD83Dx"`, `lone high surrogate \uD83D at offset 1 (line 1, column 2)`},
+		{`"\uD83DA"`, `lone high surrogate \uD83D at offset 1 (line 1, column 2)`},
+		{`"\uDE00"`, `unexpected low surrogate \uDE00 at offset 1 (line 1, column 2)`},
+	}
+	for _, tt := range errorTests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseTrailingData(t *testing.T) {
+	errorTests := []struct {
+		input string
+		want  string
+	}{
+		{`{"a":1} garbage`, `unexpected trailing data at offset 8 (line 1, column 9)`},
+		{`{"a":1} {"b":2}`, `unexpected trailing data at offset 8 (line 1, column 9)`},
+		{`12 34`, `unexpected trailing data at offset 3 (line 1, column 4)`},
+		{`[1] 2`, `unexpected trailing data at offset 4 (line 1, column 5)`},
+	}
+	for _, tt := range errorTests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	// Trailing whitespace is not trailing data
+	if got := mustParse(t, "{\"a\":1} \n\t\r\n").String(); got != `{"a":1}` {
+		t.Errorf("got %s, want {\"a\":1}", got)
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	input := `{"a": 1, "b": 2, "a": 3}`
+
+	// By default the last value wins and the key keeps its first position
+	if got := mustParse(t, input).String(); got != `{"a":3,"b":2}` {
+		t.Errorf("got %s, want {\"a\":3,\"b\":2}", got)
+	}
+
+	p := NewJSONParser([]byte(input))
+	p.DisallowDuplicateKeys = true
+	if _, err := p.Parse(); err == nil || err.Error() != `duplicate key "a" at offset 17 (line 1, column 18)` {
+		t.Errorf("DisallowDuplicateKeys: got error %v", err)
+	}
+}
+
+func TestTrailingCommas(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{`[1, 2, 3,]`, `[1,2,3]`, `trailing comma before ']' at offset 9 (line 1, column 10)`},
+		{`{"a": 1,}`, `{"a":1}`, `trailing comma before '}' at offset 8 (line 1, column 9)`},
+		{`[[1,],]`, `[[1]]`, `trailing comma before ']' at offset 4 (line 1, column 5)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.wantErr {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.wantErr)
+		}
+
+		p := NewJSONParser([]byte(tt.input))
+		p.AllowTrailingCommas = true
+		value, err := p.Parse()
+		if err != nil || value.String() != tt.want {
+			t.Errorf("AllowTrailingCommas: Parse(%s) = %v, %v, want %s", tt.input, value, err, tt.want)
+		}
+	}
+
+	// A comma still needs a value in front of it
+	for _, input := range []string{`[,]`, `[1,,2]`, `{,}`} {
+		p := NewJSONParser([]byte(input))
+		p.AllowTrailingCommas = true
+		if _, err := p.Parse(); err == nil {
+			t.Errorf("AllowTrailingCommas: Parse(%s) succeeded", input)
+		}
+	}
+}
+
+func TestParseNumberTokens(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`7`, `7`},
+		{`12345`, `12345`},
+		{`-0.25e2`, `-25`},
+		{`[7]`, `[7]`},
+		{`[1,23,456]`, `[1,23,456]`},
+		{`{"a":9}`, `{"a":9}`},
+		{`{"a":98,"b":7}`, `{"a":98,"b":7}`},
+		{`[1.5 , 2]`, `[1.5,2]`},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.input).String(); got != tt.want {
+			t.Errorf("Parse(%s) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalidNumbers(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`01`, `invalid number "01", leading zeros are not allowed at offset 0 (line 1, column 1)`},
+		{`1.`, `invalid number "1." at offset 0 (line 1, column 1)`},
+		{`.5`, `unexpected character '.' at offset 0 (line 1, column 1)`},
+		{`1e+`, `invalid number "1e+" at offset 0 (line 1, column 1)`},
+		{`-`, `invalid number "-" at offset 0 (line 1, column 1)`},
+		{`1.2.3`, `invalid number "1.2.3" at offset 0 (line 1, column 1)`},
+		{`--5`, `invalid number "--5" at offset 0 (line 1, column 1)`},
+		{`1e`, `invalid number "1e" at offset 0 (line 1, column 1)`},
+		{`[1, 2-]`, `invalid number "2-" at offset 4 (line 1, column 5)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{`0`, `-0`, `0.5`, `1e5`, `1E-5`, `-12.5e+3`} {
+		mustParse(t, input)
+	}
+}
+
+func TestUseNumberKeepsPrecision(t *testing.T) {
+	input := `{"id":10000000000000001}`
+
+	// float64 cannot hold the id
+	if got := mustParse(t, input).String(); got != `{"id":10000000000000000}` {
+		t.Errorf("without UseNumber got %s", got)
+	}
+
+	p := NewJSONParser([]byte(input))
+	p.UseNumber = true
+	value, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := value.String(); got != input {
+		t.Errorf("UseNumber round trip = %s, want %s", got, input)
+	}
+	id, _ := value.Get("id")
+	if n, err := id.AsInt64(); err != nil || n != 10000000000000001 {
+		t.Errorf("AsInt64() = %d, %v, want 10000000000000001", n, err)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	deep := strings.Repeat("[", DefaultMaxDepth+1) + strings.Repeat("]", DefaultMaxDepth+1)
+	want := fmt.Sprintf("exceeded maximum nesting depth of %d at offset %d (line 1, column %d)", DefaultMaxDepth, DefaultMaxDepth, DefaultMaxDepth+1)
+	if got := parseError(t, deep); got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+
+	// Exactly at the limit is fine
+	mustParse(t, deep[1:len(deep)-1])
+
+	p := NewJSONParser([]byte(`{"a": [[1]]}`))
+	p.MaxDepth = 2
+	if _, err := p.Parse(); err == nil || err.Error() != "exceeded maximum nesting depth of 2 at offset 7 (line 1, column 8)" {
+		t.Errorf("MaxDepth 2: got error %v", err)
+	}
+}
+
+func TestUnterminatedString(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"name": "John`, `unterminated string at offset 9 (line 1, column 10)`},
+		{`"`, `unterminated string at offset 0 (line 1, column 1)`},
+		{`"escaped quote at the end\"`, `unterminated string at offset 0 (line 1, column 1)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyInput(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", `unexpected end of input, document is empty at offset 0 (line 1, column 1)`},
+		{"   ", `unexpected end of input, document is empty at offset 3 (line 1, column 4)`},
+		{"\n", `unexpected end of input, document is empty at offset 1 (line 2, column 1)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%q) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestByteOrderMark(t *testing.T) {
+	if got := mustParse(t, "\xEF\xBB\xBF{\"a\": [1]}").String(); got != `{"a":[1]}` {
+		t.Errorf("got %s, want {\"a\":[1]}", got)
+	}
+
+	// Offsets still count the BOM, and only a leading one is skipped
+	if got := parseError(t, "\xEF\xBB\xBF{\"a\": x}"); got != `unexpected character 'x' at offset 9 (line 1, column 10)` {
+		t.Errorf("got error %q", got)
+	}
+	if got := parseError(t, "{\"a\": 1}\xEF\xBB\xBF"); got != `unexpected trailing data at offset 8 (line 1, column 9)` {
+		t.Errorf("got error %q", got)
+	}
+}
+
+func TestNewJSONParserFromReader(t *testing.T) {
+	value, err := NewJSONParserFromReader(strings.NewReader(sampleDocument)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !value.Equal(mustParse(t, sampleDocument)) {
+		t.Errorf("reader parse differs from Parse: %s", value)
+	}
+
+	_, err = NewJSONParserFromReader(strings.NewReader("{\n  \"a\": }")).Parse()
+	if err == nil || err.Error() != "expected value but found '}' at offset 9 (line 2, column 8)" {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestParseObjectFields(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"a": 1, "b": 2}`, `{"a":1,"b":2}`},
+		{`{"a": "x", "b": [true, null]}`, `{"a":"x","b":[true,null]}`},
+		{`{"a": {"b": 1, "c": 2}, "d": 3}`, `{"a":{"b":1,"c":2},"d":3}`},
+	}
+	for _, tt := range tests {
+		value := mustParse(t, tt.input)
+		if got := value.String(); got != tt.want {
+			t.Errorf("Parse(%s) = %s, want %s", tt.input, got, tt.want)
+		}
+		if n := value.Len(); n != 2 {
+			t.Errorf("Parse(%s) has %d fields, want 2", tt.input, n)
+		}
+	}
+}
+
+func TestParseString(t *testing.T) {
+	for _, input := range []string{sampleDocument, `"a\nb"`, `[1, 2`, `{"a" 1}`, ``} {
+		want, wantErr := Parse([]byte(input))
+		got, err := ParseString(input)
+		if fmt.Sprint(err) != fmt.Sprint(wantErr) {
+			t.Errorf("ParseString(%q) error = %v, want %v", input, err, wantErr)
+		} else if !got.Equal(want) {
+			t.Errorf("ParseString(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{sampleDocument, true},
+		{`[1, "two", {"three": null}]`, true},
+		{`42`, true},
+		{``, false},
+		{`{"a": 1,}`, false},
+		{`[1, 2`, false},
+		{`{"a" 1}`, false},
+		{`"unterminated`, false},
+		{`[01]`, false},
+		{`{} {}`, false},
+		{`tru`, false},
+	}
+	for _, tt := range tests {
+		if got := Valid([]byte(tt.input)); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestControlCharacters(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"\"a\tb\"", `invalid control character '\t' in string at offset 2 (line 1, column 3)`},
+		{"\"a\nb\"", `invalid control character '\n' in string at offset 2 (line 1, column 3)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.err {
+			t.Errorf("Parse(%q) error = %q, want %q", tt.input, got, tt.err)
+		}
+
+		p := NewJSONParser([]byte(tt.input))
+		p.AllowControlCharacters = true
+		value, err := p.Parse()
+		if want := tt.input[1 : len(tt.input)-1]; err != nil || value.Value != want {
+			t.Errorf("AllowControlCharacters: Parse(%q) = %v, %v, want %q", tt.input, value.Value, err, want)
+		}
+	}
+}
+
+func TestSingleQuotes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`'single'`, `"single"`},
+		{`"double"`, `"double"`},
+		{`{'a': "x", "b": 'y'}`, `{"a":"x","b":"y"}`},
+		{`'say "hi"'`, `"say \"hi\""`},
+		{`'it\'s'`, `"it's"`},
+		{`"it's"`, `"it's"`},
+	}
+	for _, tt := range tests {
+		p := NewJSONParser([]byte(tt.input))
+		p.AllowSingleQuotes = true
+		value, err := p.Parse()
+		if err != nil || value.String() != tt.want {
+			t.Errorf("AllowSingleQuotes: Parse(%s) = %s, %v, want %s", tt.input, value, err, tt.want)
+		}
+	}
+
+	want := `unexpected character '\'', single quoted strings are not allowed at offset 0 (line 1, column 1)`
+	if got := parseError(t, `'single'`); got != want {
+		t.Errorf("Parse('single') error = %q, want %q", got, want)
+	}
+}
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"{\n\t// name\n\t\"a\": 1, /* count */ \"b\": 2 // last\n}", `{"a":1,"b":2}`},
+		{"[1, // one\n 2, /* two */ 3 /* end */]", `[1,2,3]`},
+		{"/* leading */ [/**/] // trailing", `[]`},
+	}
+	for _, tt := range tests {
+		p := NewJSONParser([]byte(tt.input))
+		p.AllowComments = true
+		value, err := p.Parse()
+		if err != nil || value.String() != tt.want {
+			t.Errorf("AllowComments: Parse(%q) = %s, %v, want %s", tt.input, value, err, tt.want)
+		}
+
+		if _, err := Parse([]byte(tt.input)); err == nil || !strings.Contains(err.Error(), "unexpected character '/'") {
+			t.Errorf("Parse(%q) error = %v, want unexpected character '/'", tt.input, err)
+		}
+	}
+}
+
+func TestErrorLineAndColumn(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"{\n  \"a\": 1,\n  \"b\" 2\n}", `expected ':' but found 2 at offset 18 (line 3, column 7)`},
+		{"[\r\n1,\r\n\t@]", `unexpected character '@' at offset 8 (line 3, column 2)`},
+		{"[1,\n\n", `unexpected end of input at offset 5 (line 3, column 1)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.err {
+			t.Errorf("Parse(%q) error = %q, want %q", tt.input, got, tt.err)
+		}
+	}
+}
+
+// largeArray is an array of 10000 numbers
+var largeArray = "[" + strings.Repeat("12345,", 9999) + "12345]"
+
+func TestParseLargeArray(t *testing.T) {
+	if n := mustParse(t, largeArray).Len(); n != 10000 {
+		t.Errorf("Len() = %d, want 10000", n)
+	}
+}
+
+func BenchmarkParseLargeArray(b *testing.B) {
+	b.SetBytes(int64(len(largeArray)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseString(largeArray); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// literalArray is an array of 10000 literals, whose elements need no allocation of their own
+var literalArray = "[" + strings.Repeat("true,", 9999) + "true]"
+
+func TestParseArrayAllocations(t *testing.T) {
+	// Reserving the scratch stack from the input size avoids growing it element by element
+	allocs := testing.AllocsPerRun(10, func() { ParseString(literalArray) })
+	if allocs > 5 {
+		t.Errorf("Parse() made %v allocations for an array of 10000 literals, want at most 5", allocs)
+	}
+}
+
+func BenchmarkParseLiteralArray(b *testing.B) {
+	b.SetBytes(int64(len(literalArray)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseString(literalArray); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// escapedStrings is an array of 5000 strings that each contain escape sequences
+var escapedStrings = "[" + strings.Repeat(`"line\tone\nline \"two\" é\\",`, 4999) + `"end"]`
+
+func TestParseEscapedStrings(t *testing.T) {
+	element, ok := mustParse(t, escapedStrings).Index(0)
+	if want := "line\tone\nline \"two\" é\\"; !ok || element.Value != want {
+		t.Errorf("Index(0) = %q, %v, want %q", element.Value, ok, want)
+	}
+}
+
+func BenchmarkParseEscapedStrings(b *testing.B) {
+	b.SetBytes(int64(len(escapedStrings)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseString(escapedStrings); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParseObjectAndArray(t *testing.T) {
+	if fields, err := ParseObject([]byte(`{"a": 1}`)); err != nil || len(fields) != 1 {
+		t.Errorf("ParseObject() = %v, %v, want one field", fields, err)
+	}
+	if elements, err := ParseArray([]byte(`[1, 2]`)); err != nil || len(elements) != 2 {
+		t.Errorf("ParseArray() = %v, %v, want two elements", elements, err)
+	}
+
+	tests := []struct {
+		name  string
+		parse func([]byte) error
+		input string
+		err   string
+	}{
+		{"ParseObject", func(b []byte) error { _, err := ParseObject(b); return err }, `[1]`, "expected object, got array"},
+		{"ParseObject", func(b []byte) error { _, err := ParseObject(b); return err }, `"a"`, "expected object, got string"},
+		{"ParseArray", func(b []byte) error { _, err := ParseArray(b); return err }, `{}`, "expected array, got object"},
+		{"ParseArray", func(b []byte) error { _, err := ParseArray(b); return err }, `[1`, "unexpected end of input at offset 2 (line 1, column 3)"},
+	}
+	for _, tt := range tests {
+		if err := tt.parse([]byte(tt.input)); err == nil || err.Error() != tt.err {
+			t.Errorf("%s(%s) error = %v, want %q", tt.name, tt.input, err, tt.err)
+		}
+	}
+}
+
+func TestRecordOffsets(t *testing.T) {
+	input := `{"a": {"b": [1, "two"]}}`
+	p := NewJSONParser([]byte(input))
+	p.RecordOffsets = true
+	root, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"a", `{"b": [1, "two"]}`},
+		{"a.b", `[1, "two"]`},
+		{"a.b[1]", `"two"`},
+	}
+	for _, tt := range tests {
+		value, err := root.Path(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := input[value.Start:value.End]; got != tt.want {
+			t.Errorf("%s spans %q [%d:%d], want %q", tt.path, got, value.Start, value.End, tt.want)
+		}
+	}
+	if root.Start != 0 || root.End != len(input) {
+		t.Errorf("root spans [%d:%d], want [0:%d]", root.Start, root.End, len(input))
+	}
+}
+
+func TestLeadingPlus(t *testing.T) {
+	want := `invalid number, leading '+' is not allowed at offset 0 (line 1, column 1)`
+	if got := parseError(t, `+5`); got != want {
+		t.Errorf("Parse(+5) error = %q, want %q", got, want)
+	}
+	for input, want := range map[string]float64{`1e+5`: 1e5, `2.5E+2`: 250} {
+		if got := mustParse(t, input).Value; got != want {
+			t.Errorf("Parse(%s) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestLeadingZeros(t *testing.T) {
+	for input, want := range map[string]float64{`0`: 0, `0.5`: 0.5, `-0.25`: -0.25, `0e1`: 0} {
+		if got := mustParse(t, input).Value; got != want {
+			t.Errorf("Parse(%s) = %v, want %v", input, got, want)
+		}
+	}
+	for _, input := range []string{`00`, `012`, `-012`} {
+		want := fmt.Sprintf("invalid number %q, leading zeros are not allowed at offset 0 (line 1, column 1)", input)
+		if got := parseError(t, input); got != want {
+			t.Errorf("Parse(%s) error = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += n
+	return n, err
+}
+
+func TestMaxInputBytes(t *testing.T) {
+	input := `[1, 2, 3]`
+	want := "input exceeds the maximum size of 5 bytes"
+
+	p := NewJSONParser([]byte(input))
+	p.MaxInputBytes = 5
+	if _, err := p.Parse(); err == nil || err.Error() != want {
+		t.Errorf("Parse() error = %v, want %q", err, want)
+	}
+
+	r := &countingReader{r: strings.NewReader(input + strings.Repeat(" ", 10000))}
+	p = NewJSONParserFromReader(r)
+	p.MaxInputBytes = 5
+	if _, err := p.Parse(); err == nil || err.Error() != want {
+		t.Errorf("Parse() from a reader error = %v, want %q", err, want)
+	}
+	if r.n > 6 {
+		t.Errorf("read %d bytes from the reader, want at most 6", r.n)
+	}
+
+	p = NewJSONParser([]byte(input))
+	p.MaxInputBytes = len(input)
+	if _, err := p.Parse(); err != nil {
+		t.Errorf("Parse() at the limit error = %v", err)
+	}
+}
+
+func TestMaxStringLength(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{`"abcde"`, ""},
+		{`"abcdef"`, "string exceeds the maximum length of 5 bytes at offset 0 (line 1, column 1)"},
+		{`{"abcdef": 1}`, "string exceeds the maximum length of 5 bytes at offset 1 (line 1, column 2)"},
+		// Escapes count the bytes they decode to
+		{`"\u00e9\u00e9"`, ""},
+		{`"\u00e9\u00e9\u00e9"`, "string exceeds the maximum length of 5 bytes at offset 0 (line 1, column 1)"},
+	}
+	for _, tt := range tests {
+		p := NewJSONParser([]byte(tt.input))
+		p.MaxStringLength = 5
+		_, err := p.Parse()
+		if got := fmt.Sprint(err); (tt.err == "" && err != nil) || (tt.err != "" && got != tt.err) {
+			t.Errorf("Parse(%s) error = %v, want %q", tt.input, err, tt.err)
+		}
+	}
+}
+
+func TestNullEscape(t *testing.T) {
+	value := mustParse(t, `"\u0000abc"`)
+	s, err := value.AsString()
+	if err != nil || len(s) != 4 || s != "\x00abc" {
+		t.Errorf("Parse(\"\\u0000abc\") = %q (length %d), %v, want \"\\x00abc\" (length 4)", s, len(s), err)
+	}
+	if got := value.String(); got != `"\u0000abc"` {
+		t.Errorf("String() = %s, want \"\\u0000abc\"", got)
+	}
+}
+
+func TestExpectedTokenErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"a" }`, `expected ':' but found '}' at offset 5 (line 1, column 6)`},
+		{`{"a" 1}`, `expected ':' but found 1 at offset 5 (line 1, column 6)`},
+		{`{"a": 1 "b": 2}`, `expected ',' or '}' but found "b" at offset 8 (line 1, column 9)`},
+		{`[1 2]`, `expected ',' or ']' but found 2 at offset 3 (line 1, column 4)`},
+		{`[1, 2 }`, `expected ',' or ']' but found '}' at offset 6 (line 1, column 7)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestArrayCommas(t *testing.T) {
+	if got := mustParse(t, `[1,2]`).String(); got != `[1,2]` {
+		t.Errorf("Parse([1,2]) = %s, want [1,2]", got)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`[1 2]`, `expected ',' or ']' but found 2 at offset 3 (line 1, column 4)`},
+		{`[[1] [2]]`, `expected ',' or ']' but found '[' at offset 5 (line 1, column 6)`},
+		{`["a" "b"]`, `expected ',' or ']' but found "b" at offset 5 (line 1, column 6)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestObjectCommas(t *testing.T) {
+	if got := mustParse(t, `{"a":1,"b":2}`).String(); got != `{"a":1,"b":2}` {
+		t.Errorf(`Parse({"a":1,"b":2}) = %s`, got)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"a":1 "b":2}`, `expected ',' or '}' but found "b" at offset 7 (line 1, column 8)`},
+		{`{"a":{} "b":2}`, `expected ',' or '}' but found "b" at offset 8 (line 1, column 9)`},
+		{`{"a":1 ]`, `expected ',' or '}' but found ']' at offset 7 (line 1, column 8)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestObjectKeysMustBeStrings(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{1: "x"}`, `expected string key but found 1 at offset 1 (line 1, column 2)`},
+		{`{true: "x"}`, `expected string key but found true at offset 1 (line 1, column 2)`},
+		{`{a: "x"}`, `unexpected character 'a' at offset 1 (line 1, column 2)`},
+		{`{[]: "x"}`, `expected string key but found '[' at offset 1 (line 1, column 2)`},
+	}
+	for _, tt := range tests {
+		if got := parseError(t, tt.input); got != tt.want {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNonFiniteNumbers(t *testing.T) {
+	tests := []struct {
+		input  string
+		check  func(float64) bool
+		strict string
+	}{
+		{`NaN`, math.IsNaN, `unexpected character 'N' at offset 0 (line 1, column 1)`},
+		{`Infinity`, func(f float64) bool { return math.IsInf(f, 1) }, `unexpected character 'I' at offset 0 (line 1, column 1)`},
+		{`-Infinity`, func(f float64) bool { return math.IsInf(f, -1) }, `invalid number "-" at offset 0 (line 1, column 1)`},
+	}
+	for _, tt := range tests {
+		p := NewJSONParser([]byte(tt.input))
+		p.AllowNonFiniteNumbers = true
+		value, err := p.Parse()
+		if f, ok := value.Value.(float64); err != nil || !ok || !tt.check(f) {
+			t.Errorf("AllowNonFiniteNumbers: Parse(%s) = %v, %v", tt.input, value.Value, err)
+		}
+
+		if got := parseError(t, tt.input); got != tt.strict {
+			t.Errorf("Parse(%s) error = %q, want %q", tt.input, got, tt.strict)
+		}
+	}
+}
+
+// asciiStrings is an array of 5000 plain ASCII strings without escapes
+var asciiStrings = "[" + strings.Repeat(`"the quick brown fox jumps over the lazy dog",`, 4999) + `"end"]`
+
+func TestParsePlainAndEscapedStrings(t *testing.T) {
+	// Strings with and without escapes decode the same way
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"plain ascii"`, "plain ascii"},
+		{`"plain then \u0041"`, "plain then A"},
+		{`"\u0041 then plain"`, "A then plain"},
+		{`"caf\u00e9"`, "café"},
+		{`""`, ""},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.input).Value; got != tt.want {
+			t.Errorf("Parse(%s) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkParseASCIIStrings(b *testing.B) {
+	b.SetBytes(int64(len(asciiStrings)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseString(asciiStrings); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestExtraWhitespace(t *testing.T) {
+	input := "\f[1,\f2\v]\f"
+	want := `unexpected character '\f' at offset 0 (line 1, column 1)`
+	if got := parseError(t, input); got != want {
+		t.Errorf("Parse(%q) error = %q, want %q", input, got, want)
+	}
+
+	p := NewJSONParser([]byte(input))
+	p.ExtraWhitespace = "\f\v"
+	value, err := p.Parse()
+	if err != nil || value.String() != `[1,2]` {
+		t.Errorf("ExtraWhitespace: Parse(%q) = %s, %v, want [1,2]", input, value, err)
+	}
+
+	// Extra whitespace is only skipped between tokens
+	p = NewJSONParser([]byte("\"a\fb\""))
+	p.ExtraWhitespace = "\f"
+	if _, err := p.Parse(); err == nil {
+		t.Error("ExtraWhitespace: a raw form feed inside a string was accepted")
+	}
+}
+
+func TestTokensEndingAtDelimiters(t *testing.T) {
+	// Numbers and literals end at the byte after them, which must be left for the next token
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`[10,20]`, `[10,20]`},
+		{`{"a":-1.5e3}`, `{"a":-1500}`},
+		{`[true,false,null]`, `[true,false,null]`},
+		{`["\u00e9",1]`, `["é",1]`},
+		{`["\ud83d\ude00",12,"😀"]`, `["😀",12,"😀"]`},
+		{"[1\n,2\t]", `[1,2]`},
+		{`7`, `7`},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.input).String(); got != tt.want {
+			t.Errorf("Parse(%s) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	// The offset after a number stays in step with the input
+	want := `unexpected character '@' at offset 14 (line 1, column 15)`
+	if got := parseError(t, `["\u00e9", 123@]`); got != want {
+		t.Errorf("error = %q, want %q", got, want)
+	}
+}
+
+func TestValidateUTF8(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		// 0xC3 starts a two byte sequence but '(' is not a continuation byte
+		{"\"a\xc3(b\"", "invalid UTF-8 byte 0xc3 in string at offset 2 (line 1, column 3)"},
+		{"{\"k\xff\": 1}", "invalid UTF-8 byte 0xff in string at offset 3 (line 1, column 4)"},
+		{"\"\xe2\x82\xac\"", ""},
+	}
+	for _, tt := range tests {
+		p := NewJSONParser([]byte(tt.input))
+		p.ValidateUTF8 = true
+		_, err := p.Parse()
+		if got := fmt.Sprint(err); (tt.err == "" && err != nil) || (tt.err != "" && got != tt.err) {
+			t.Errorf("ValidateUTF8: Parse(%q) error = %v, want %q", tt.input, err, tt.err)
+		}
+
+		// Without the option invalid bytes pass through
+		if _, err := Parse([]byte(tt.input)); err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.input, err)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	p := NewJSONParser([]byte(`{"a": [1, 2]}`))
+	p.UseNumber = true
+	first, err := p.Parse()
+	if err != nil || first.String() != `{"a":[1,2]}` {
+		t.Fatalf("first Parse() = %s, %v", first, err)
+	}
+
+	p.Reset([]byte(`[true, "x"]`))
+	second, err := p.Parse()
+	if err != nil || second.String() != `[true,"x"]` {
+		t.Errorf("Parse() after Reset = %s, %v, want [true,\"x\"]", second, err)
+	}
+
+	// A failed parse leaves nothing behind, and options are kept
+	p.Reset([]byte(`[[[1, 2`))
+	if _, err := p.Parse(); err == nil {
+		t.Fatal("Parse() of an unterminated array succeeded")
+	}
+	p.Reset([]byte("\n 1.50"))
+	third, err := p.Parse()
+	if err != nil || third.Value != Number("1.50") {
+		t.Errorf("Parse() after a failed parse = %#v, %v, want Number 1.50", third.Value, err)
+	}
+	if _, err := p.Parse(); err == nil {
+		t.Error("Parse() of an exhausted input succeeded")
+	}
+}
+
+func BenchmarkParseNew(b *testing.B) {
+	input := []byte(sampleDocument)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewJSONParser(input).Parse(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseReset(b *testing.B) {
+	input := []byte(sampleDocument)
+	p := NewJSONParser(nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.Reset(input)
+		if _, err := p.Parse(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestTrailingWhitespace(t *testing.T) {
+	for _, input := range []string{"{\"a\":1}\n\n ", "[]\r\n", "1 \t"} {
+		if !Valid([]byte(input)) {
+			t.Errorf("Valid(%q) = false, want true", input)
+		}
+		mustParse(t, input)
+	}
+	if Valid([]byte("{\"a\":1}\n\n x")) {
+		t.Error("Valid() accepted data after trailing whitespace")
+	}
+}
+
+// records is an array of 10000 objects with the same keys
+var records = "[" + strings.Repeat(`{"id": 1, "name": "n", "active": true},`, 9999) + `{"id": 2, "name": "m", "active": false}]`
+
+func TestInternKeys(t *testing.T) {
+	p := NewJSONParser([]byte(`[{"id": 1, "x": 2}, {"id": 3}]`))
+	p.InternKeys = true
+	value, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := value.Index(0)
+	second, _ := value.Index(1)
+	if unsafe.StringData(first.KeyOrder[0]) != unsafe.StringData(second.KeyOrder[0]) {
+		t.Error("InternKeys: the two \"id\" keys do not share their bytes")
+	}
+	if got := value.String(); got != `[{"id":1,"x":2},{"id":3}]` {
+		t.Errorf("InternKeys: Parse() = %s", got)
+	}
+}
+
+func BenchmarkParseRecords(b *testing.B) {
+	benchmarkParseRecords(b, false)
+}
+
+func BenchmarkParseRecordsInternKeys(b *testing.B) {
+	benchmarkParseRecords(b, true)
+}
+
+func benchmarkParseRecords(b *testing.B, intern bool) {
+	input := []byte(records)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := NewJSONParser(input)
+		p.InternKeys = intern
+		if _, err := p.Parse(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestUnquotedKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   string
+	}{
+		{`{name: "x", $id_2: 1}`, `{"name":"x","$id_2":1}`, ""},
+		{`{"quoted": true, bare: null}`, `{"quoted":true,"bare":null}`, ""},
+		{`{true: 1}`, `{"true":1}`, ""},
+		{`{1a: 2}`, "", "expected string key but found 1 at offset 1 (line 1, column 2)"},
+		{`[name]`, "", "expected value but found name at offset 1 (line 1, column 2)"},
+	}
+	for _, tt := range tests {
+		p := NewJSONParser([]byte(tt.input))
+		p.AllowUnquotedKeys = true
+		value, err := p.Parse()
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("AllowUnquotedKeys: Parse(%s) error = %v, want %q", tt.input, err, tt.err)
+			}
+		} else if err != nil || value.String() != tt.want {
+			t.Errorf("AllowUnquotedKeys: Parse(%s) = %s, %v, want %s", tt.input, value, err, tt.want)
+		}
+	}
+
+	// Strict mode still requires quoted keys
+	if got, want := parseError(t, `{"quoted": 1, bare: 2}`), `unexpected character 'b' at offset 14 (line 1, column 15)`; got != want {
+		t.Errorf("Parse() error = %q, want %q", got, want)
+	}
+	if got := mustParse(t, `{"quoted": 1}`).String(); got != `{"quoted":1}` {
+		t.Errorf("Parse() = %s, want {\"quoted\":1}", got)
+	}
+}
+
+func TestValidAllocations(t *testing.T) {
+	// Valid allocates the parser but nothing per token, however large the document
+	small := []byte(sampleDocument)
+	large := []byte("[" + strings.Repeat(sampleDocument+",", 100) + "1]")
+	smallAllocs := testing.AllocsPerRun(10, func() { Valid(small) })
+	largeAllocs := testing.AllocsPerRun(10, func() { Valid(large) })
+	if largeAllocs != smallAllocs {
+		t.Errorf("Valid() made %v allocations for a large document and %v for a small one, want the same", largeAllocs, smallAllocs)
+	}
+	if parseAllocs := testing.AllocsPerRun(10, func() { Parse(small) }); smallAllocs*5 > parseAllocs {
+		t.Errorf("Valid() made %v allocations, Parse() %v", smallAllocs, parseAllocs)
+	}
+}
+
+func TestSyntaxOnlyErrors(t *testing.T) {
+	// Without token text, errors show the token as written in the input
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"a" "b\n"}`, `expected ':' but found "b\n" at offset 5 (line 1, column 6)`},
+		{`[1 2.5]`, `expected ',' or ']' but found 2.5 at offset 3 (line 1, column 4)`},
+		{`[01]`, `invalid number "01", leading zeros are not allowed at offset 1 (line 1, column 2)`},
+	}
+	for _, tt := range tests {
+		p := NewJSONParser([]byte(tt.input))
+		p.discard = true
+		if _, err := p.Parse(); err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%s) checking syntax only error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func BenchmarkValid(b *testing.B) {
+	input := []byte(sampleDocument)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !Valid(input) {
+			b.Fatal("Valid() = false")
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package jsonparser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Encoder writes JSON values to an output stream, one per line.
+// Its exported fields configure encoding and may be set before calling Encode.
+type Encoder struct {
+	// SortKeys writes object keys in lexicographic order instead of insertion order
+	SortKeys bool
+	// EscapeHTML writes <, > and & in strings as \u003c, \u003e and \u0026 so the
+	// output can be embedded in HTML. NewEncoder enables it, like Marshal does.
+	EscapeHTML bool
+	// AllowNonFiniteNumbers writes NaN and infinite numbers as the bare tokens
+	// NaN, Infinity and -Infinity instead of failing
+	AllowNonFiniteNumbers bool
+	// Colorizer, when set, wraps object keys and scalar values, see MarshalColored
+	Colorizer Colorizer
+
+	w      io.Writer
+	prefix string
+	indent string
+}
+
+// NewEncoder creates a new Encoder writing to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, EscapeHTML: true}
+}
+
+// SetIndent makes the Encoder indent its output like MarshalIndent
+func (enc *Encoder) SetIndent(prefix, indent string) {
+	enc.prefix = prefix
+	enc.indent = indent
+}
+
+// Encode writes the JSON encoding of the value to the stream, followed by a newline
+func (enc *Encoder) Encode(v JSONValue) error {
+	e := &encoder{
+		prefix:         enc.prefix,
+		indent:         enc.indent,
+		sortKeys:       enc.SortKeys,
+		escapeHTML:     enc.EscapeHTML,
+		allowNonFinite: enc.AllowNonFiniteNumbers,
+		colorizer:      enc.Colorizer,
+	}
+	if _, err := e.writeTo(enc.w, v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(enc.w, "\n")
+
+	return err
+}
+
+// encoder writes the JSON encoding of values through a buffered writer
+type encoder struct {
+	buf            *bufio.Writer
+	prefix         string
+	indent         string
+	sortKeys       bool
+	escapeHTML     bool
+	allowNonFinite bool
+	colorizer      Colorizer
+	depth          int
+}
+
+// countingWriter counts the bytes successfully written to the underlying writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes to the underlying writer and adds the written bytes to the count
+func (c *countingWriter) Write(data []byte) (int, error) {
+	n, err := c.w.Write(data)
+	c.n += int64(n)
+	return n, err
+}
+
+// Marshal returns the compact JSON encoding of the value.
+// Like encoding/json it escapes <, > and & in strings, see Encoder.EscapeHTML.
+func (v JSONValue) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := v.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalIndent is like Marshal but places each element on its own line,
+// starting with prefix and followed by one copy of indent per nesting level
+func (v JSONValue) MarshalIndent(prefix, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	e := &encoder{prefix: prefix, indent: indent, escapeHTML: true}
+	if _, err := e.writeTo(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Minify checks that data is a JSON document and returns it in compact form. Like
+// json.Compact it only drops the whitespace between tokens, so strings, numbers and
+// repeated keys are kept byte for byte, including <, > and & and invalid UTF-8.
+func Minify(data []byte) ([]byte, error) {
+	parser := NewJSONParser(data)
+	parser.discard = true
+	if _, err := parser.Parse(); err != nil {
+		return nil, err
+	}
+
+	return stripWhitespace(bytes.TrimPrefix(data, utf8BOM)), nil
+}
+
+// stripWhitespace returns a copy of a valid document without the whitespace outside its strings
+func stripWhitespace(data []byte) []byte {
+	compact := make([]byte, 0, len(data))
+	inString, escaped := false, false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case !inString && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			continue
+		}
+		compact = append(compact, c)
+	}
+
+	return compact
+}
+
+// Prettify parses a JSON document and returns it with each element on its own
+// line, indented by one copy of indent per nesting level. Like json.Indent it
+// leaves <, > and & unescaped.
+func Prettify(data []byte, indent string) ([]byte, error) {
+	return reformat(data, &encoder{indent: indent})
+}
+
+// reformat parses a document and writes it out again with e
+func reformat(data []byte, e *encoder) ([]byte, error) {
+	value, err := parseReformat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := e.writeTo(&buf, value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseReformat parses a document that is only going to be written out again,
+// so numbers keep their original text
+func parseReformat(data []byte) (JSONValue, error) {
+	parser := NewJSONParser(data)
+	parser.UseNumber = true
+
+	return parser.Parse()
+}
+
+// WriteTo streams the compact JSON encoding of the value to w and returns the number of bytes written
+func (v JSONValue) WriteTo(w io.Writer) (int64, error) {
+	return (&encoder{escapeHTML: true}).writeTo(w, v)
+}
+
+// MarshalToBuilder appends the compact JSON encoding of the value to b, for callers
+// that want the result as a string. If it fails b may hold part of the encoding.
+func (v JSONValue) MarshalToBuilder(b *strings.Builder) error {
+	_, err := v.WriteTo(b)
+
+	return err
+}
+
+// writeTo encodes the value to w, returning the number of bytes that reached it
+func (e *encoder) writeTo(w io.Writer, v JSONValue) (int64, error) {
+	counter := &countingWriter{w: w}
+	e.buf = bufio.NewWriter(counter)
+	e.encodeBefore(v)
+	if err := e.encodeValue(v); err != nil {
+		return counter.n, err
+	}
+	e.encodeAfter(v)
+	err := e.buf.Flush()
+
+	return counter.n, err
+}
+
+// String renders the value as compact JSON
+func (v JSONValue) String() string {
+	data, err := v.Marshal()
+	if err != nil {
+		return fmt.Sprintf("<invalid JSONValue: %v>", err)
+	}
+
+	return string(data)
+}
+
+// pretty reports whether the encoder produces indented output
+func (e *encoder) pretty() bool {
+	return e.prefix != "" || e.indent != ""
+}
+
+// newline starts a new indented line when pretty printing
+func (e *encoder) newline() {
+	if !e.pretty() {
+		return
+	}
+	e.buf.WriteByte('\n')
+	e.buf.WriteString(e.prefix)
+	for i := 0; i < e.depth; i++ {
+		e.buf.WriteString(e.indent)
+	}
+}
+
+// encodeValue writes a single value of any type
+func (e *encoder) encodeValue(v JSONValue) error {
+	if e.colorizer != nil {
+		if segment, ok := valueSegment(v.Kind); ok {
+			before, after := e.colorizer.Color(segment)
+			e.buf.WriteString(before)
+			defer e.buf.WriteString(after)
+		}
+	}
+
+	switch v.Kind {
+	case KindNull:
+		e.buf.WriteString("null")
+	case KindBool:
+		e.buf.WriteString(strconv.FormatBool(v.Value.(bool)))
+	case KindNumber:
+		if num, ok := v.Value.(Number); ok {
+			return e.encodeRawNumber(num)
+		}
+		return e.encodeNumber(v.Value.(float64))
+	case KindString:
+		e.encodeString(v.Value.(string))
+	case KindArray:
+		return e.encodeArray(v)
+	case KindObject:
+		return e.encodeObject(v)
+	default:
+		return fmt.Errorf("marshal: unknown value kind %v", v.Kind)
+	}
+
+	return nil
+}
+
+// encodeNumber writes a number using the same formatting as encoding/json
+func (e *encoder) encodeNumber(num float64) error {
+	if math.IsNaN(num) || math.IsInf(num, 0) {
+		if !e.allowNonFinite {
+			return fmt.Errorf("marshal: unsupported number %v", num)
+		}
+		e.buf.WriteString(nonFiniteText(num))
+		return nil
+	}
+
+	// Use exponent notation only for very small or very large magnitudes
+	format := byte('f')
+	if abs := math.Abs(num); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	b := strconv.AppendFloat(nil, num, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	e.buf.Write(b)
+
+	return nil
+}
+
+// encodeRawNumber writes a number kept in its original textual form
+func (e *encoder) encodeRawNumber(num Number) error {
+	if !isValidNumber([]byte(num)) {
+		// Non-finite numbers keep no original text worth preserving
+		if f, err := num.Float64(); e.allowNonFinite && err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			e.buf.WriteString(nonFiniteText(f))
+			return nil
+		}
+		return fmt.Errorf("marshal: invalid number %q", num)
+	}
+	e.buf.WriteString(string(num))
+
+	return nil
+}
+
+// nonFiniteText returns the token written for a NaN or infinite number
+func nonFiniteText(num float64) string {
+	switch {
+	case math.IsNaN(num):
+		return "NaN"
+	case num > 0:
+		return "Infinity"
+	default:
+		return "-Infinity"
+	}
+}
+
+// encodeString writes a quoted string, escaping characters that are not allowed raw
+func (e *encoder) encodeString(s string) {
+	const hex = "0123456789abcdef"
+
+	e.buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				e.buf.WriteByte('\\')
+				e.buf.WriteByte(c)
+			case c == '\n':
+				e.buf.WriteString(`\n`)
+			case c == '\r':
+				e.buf.WriteString(`\r`)
+			case c == '\t':
+				e.buf.WriteString(`\t`)
+			case c < 0x20 || (e.escapeHTML && (c == '<' || c == '>' || c == '&')):
+				e.buf.WriteString(`\u00`)
+				e.buf.WriteByte(hex[c>>4])
+				e.buf.WriteByte(hex[c&0xF])
+			default:
+				e.buf.WriteByte(c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			// Replace invalid UTF-8 with the replacement character
+			e.buf.WriteString("\ufffd")
+		case r == '\u2028' || r == '\u2029':
+			// Line and paragraph separators break JavaScript string literals
+			fmt.Fprintf(e.buf, `\u%04x`, r)
+		default:
+			e.buf.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	e.buf.WriteByte('"')
+}
+
+// encodeKey writes an object key, wrapped by the colorizer if there is one
+func (e *encoder) encodeKey(key string) {
+	if e.colorizer == nil {
+		e.encodeString(key)
+		return
+	}
+
+	before, after := e.colorizer.Color(SegmentKey)
+	e.buf.WriteString(before)
+	e.encodeString(key)
+	e.buf.WriteString(after)
+}
+
+// encodeArray writes an array and its elements
+func (e *encoder) encodeArray(v JSONValue) error {
+	array := v.Value.([]JSONValue)
+	if len(array) == 0 && !v.hasEndComments() {
+		e.buf.WriteString("[]")
+		return nil
+	}
+
+	e.buf.WriteByte('[')
+	e.depth++
+	for i, element := range array {
+		e.newline()
+		e.encodeBefore(element)
+		if err := e.encodeValue(element); err != nil {
+			return err
+		}
+		if i < len(array)-1 {
+			e.buf.WriteByte(',')
+		}
+		e.encodeAfter(element)
+	}
+	e.encodeEnd(v)
+	e.depth--
+	e.newline()
+	e.buf.WriteByte(']')
+
+	return nil
+}
+
+// encodeObject writes an object with its keys in insertion order, or sorted when sortKeys is set
+func (e *encoder) encodeObject(v JSONValue) error {
+	object := v.Value.(map[string]JSONValue)
+	keys := v.orderedKeys()
+	if e.sortKeys {
+		keys = sortedKeys(object)
+	}
+	if len(keys) == 0 && !v.hasEndComments() {
+		e.buf.WriteString("{}")
+		return nil
+	}
+
+	e.buf.WriteByte('{')
+	e.depth++
+	for i, key := range keys {
+		field := object[key]
+		e.newline()
+		e.encodeBefore(field)
+		e.encodeKey(key)
+		e.buf.WriteByte(':')
+		if e.pretty() {
+			e.buf.WriteByte(' ')
+		}
+		if err := e.encodeValue(field); err != nil {
+			return err
+		}
+		if i < len(keys)-1 {
+			e.buf.WriteByte(',')
+		}
+		e.encodeAfter(field)
+	}
+	e.encodeEnd(v)
+	e.depth--
+	e.newline()
+	e.buf.WriteByte('}')
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package jsonparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMarshalMatchesEncodingJSON(t *testing.T) {
+	// Keys are in alphabetical order, which is how encoding/json writes maps
+	inputs := []string{
+		`null`,
+		`true`,
+		`[]`,
+		`{}`,
+		`{"a": 1, "b": [true, false, null], "c": {"d": "e"}}`,
+		`[0, -1, 3.25, 1e21, 1e-7, 123456789, 0.000001, -0.5]`,
+		`["quote \" backslash \\ slash /", "tab\tnewline\ncr\r", "\u0001\u001f", "<html> & </html>"]`,
+		`["é€😀", "  "]`,
+	}
+	for _, input := range inputs {
+		got, err := mustParse(t, input).Marshal()
+		if err != nil {
+			t.Fatalf("Marshal(%s): %v", input, err)
+		}
+
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(input), &decoded); err != nil {
+			t.Fatal(err)
+		}
+		want, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("Marshal(%s) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	input := `{"name": "x", "list": [1, {"nested": [[], {}]}], "ok": false}`
+	data, err := mustParse(t, input).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := mustParse(t, string(data)); !again.Equal(mustParse(t, input)) {
+		t.Errorf("round trip changed the value: %s", data)
+	}
+}
+
+func TestString(t *testing.T) {
+	value := mustParse(t, `{"b": [1, true], "a": null}`)
+	if got := value.String(); got != `{"b":[1,true],"a":null}` {
+		t.Errorf("String() = %s", got)
+	}
+	if got := fmt.Sprint(value); got != `{"b":[1,true],"a":null}` {
+		t.Errorf("fmt.Sprint = %s", got)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	value := mustParse(t, sampleDocument)
+	want, err := value.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := value.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("WriteTo() wrote %s, want %s", buf.String(), want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("WriteTo() = %d, want %d", n, len(want))
+	}
+}
+
+// whitespaceOutsideStrings reports whether data has whitespace outside its string literals
+func whitespaceOutsideStrings(data []byte) bool {
+	inString, escaped := false, false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case !inString && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestMinify(t *testing.T) {
+	got, err := Minify([]byte(sampleDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if whitespaceOutsideStrings(got) {
+		t.Errorf("Minify() = %s, has whitespace outside strings", got)
+	}
+	want := `{"name":"John Doe","age":30,"email":"john.doe@example.com","active":true,` +
+		`"address":{"city":"New York","zip":"10001"},"tags":["golang","json","parser",[1,2,3]]}`
+	if string(got) != want {
+		t.Errorf("Minify() = %s, want %s", got, want)
+	}
+
+	// Strings and numbers are kept exactly as written
+	got, err = Minify([]byte("[ \"a  b\\t\" ,\n 1.50e+3 ]"))
+	if want := `["a  b\t",1.50e+3]`; err != nil || string(got) != want {
+		t.Errorf("Minify() = %s, %v, want %s", got, err, want)
+	}
+
+	if _, err := Minify([]byte(`{"a": }`)); err == nil {
+		t.Error("Minify() of invalid JSON succeeded")
+	}
+}
+
+func TestMinifyKeepsDocument(t *testing.T) {
+	// Only whitespace is removed, whatever a parsed value would have made of the rest
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"a": "x", "a": 2}`, `{"a":"x","a":2}`},
+		{"[\"\xff \xfe\", \"ok\"]", "[\"\xff \xfe\",\"ok\"]"},
+		{`[ "\u00e9\\", "\" ]" , 1E2 ]`, `["\u00e9\\","\" ]",1E2]`},
+		{"\xEF\xBB\xBF [ 1 ]", `[1]`},
+	}
+	for _, tt := range tests {
+		if got, err := Minify([]byte(tt.input)); err != nil || string(got) != tt.want {
+			t.Errorf("Minify(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestPrettify(t *testing.T) {
+	got, err := Prettify([]byte(`{"a":1, "b":[true, {}], "c":{"d":"x"}, "e":[]}`), "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "a": 1,
+  "b": [
+    true,
+    {}
+  ],
+  "c": {
+    "d": "x"
+  },
+  "e": []
+}`
+	if string(got) != want {
+		t.Errorf("Prettify() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, err := Prettify([]byte(`[1.0]`), "\t"); err != nil || string(got) != "[\n\t1.0\n]" {
+		t.Errorf("Prettify() = %q, %v, want \"[\\n\\t1.0\\n]\"", got, err)
+	}
+}
+
+func TestEncoderSortKeys(t *testing.T) {
+	value := mustParse(t, `{"zip": 1, "b": {"y": true, "a": null}, "a": [{"d": 1, "c": 2}]}`)
+	tests := []struct {
+		indent string
+		want   string
+	}{
+		{"", `{"a":[{"c":2,"d":1}],"b":{"a":null,"y":true},"zip":1}` + "\n"},
+		{" ", "{\n \"a\": [\n  {\n   \"c\": 2,\n   \"d\": 1\n  }\n ],\n \"b\": {\n  \"a\": null,\n  \"y\": true\n },\n \"zip\": 1\n}\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		enc.SortKeys = true
+		enc.SetIndent("", tt.indent)
+		if err := enc.Encode(value); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("Encode() with indent %q = %q, want %q", tt.indent, buf.String(), tt.want)
+		}
+	}
+
+	// Marshal keeps insertion order
+	if got, want := value.String(), `{"zip":1,"b":{"y":true,"a":null},"a":[{"d":1,"c":2}]}`; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestEscapeHTML(t *testing.T) {
+	value := mustParse(t, `"<script>a && b</script>"`)
+	escaped := `"\u003cscript\u003ea \u0026\u0026 b\u003c/script\u003e"`
+	unescaped := `"<script>a && b</script>"`
+
+	if got, err := value.Marshal(); err != nil || string(got) != escaped {
+		t.Errorf("Marshal() = %s, %v, want %s", got, err, escaped)
+	}
+
+	for escapeHTML, want := range map[bool]string{true: escaped, false: unescaped} {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		enc.EscapeHTML = escapeHTML
+		if err := enc.Encode(value); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSuffix(buf.String(), "\n"); got != want {
+			t.Errorf("Encode() with EscapeHTML %v = %s, want %s", escapeHTML, got, want)
+		}
+	}
+}
+
+func TestReformatKeepsHTML(t *testing.T) {
+	input := []byte(`{"html": "<b>&amp;</b>"}`)
+	if got, err := Minify(input); err != nil || string(got) != `{"html":"<b>&amp;</b>"}` {
+		t.Errorf("Minify() = %s, %v, want <b> kept", got, err)
+	}
+	if got, err := Prettify(input, "  "); err != nil || string(got) != "{\n  \"html\": \"<b>&amp;</b>\"\n}" {
+		t.Errorf("Prettify() = %s, %v, want <b> kept", got, err)
+	}
+}
+
+func TestEncodeNonFiniteNumbers(t *testing.T) {
+	value := JSONValue{Kind: KindArray, Value: []JSONValue{
+		{Kind: KindNumber, Value: math.NaN()},
+		{Kind: KindNumber, Value: math.Inf(1)},
+		{Kind: KindNumber, Value: math.Inf(-1)},
+	}}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.AllowNonFiniteNumbers = true
+	if err := enc.Encode(value); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "[NaN,Infinity,-Infinity]\n"; got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+
+	if _, err := value.Marshal(); err == nil || err.Error() != "marshal: unsupported number NaN" {
+		t.Errorf("Marshal() error = %v, want unsupported number NaN", err)
+	}
+}
+
+func TestMarshalToBuilder(t *testing.T) {
+	for _, input := range []string{sampleDocument, `[]`, `"<a&b>"`, `null`} {
+		value := mustParse(t, input)
+		want, err := value.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var b strings.Builder
+		b.WriteString("prefix:")
+		if err := value.MarshalToBuilder(&b); err != nil {
+			t.Fatalf("MarshalToBuilder() error = %v", err)
+		}
+		if got := b.String(); got != "prefix:"+string(want) {
+			t.Errorf("MarshalToBuilder() = %s, want prefix:%s", got, want)
+		}
+	}
+
+	var b strings.Builder
+	bad := JSONValue{Kind: KindNumber, Value: math.Inf(1)}
+	if err := bad.MarshalToBuilder(&b); err == nil {
+		t.Error("MarshalToBuilder() of +Inf succeeded")
+	}
+}
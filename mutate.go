@@ -0,0 +1,97 @@
+package jsonparser
+
+import "slices"
+
+// Set inserts or replaces a field of an object. New keys are added after the
+// existing ones, and replaced keys keep their position.
+func (v *JSONValue) Set(key string, value JSONValue) error {
+	if v.Kind != KindObject {
+		return typeError(KindObject, *v)
+	}
+	object, _ := v.Value.(map[string]JSONValue)
+	if object == nil {
+		object = make(map[string]JSONValue)
+		v.Value = object
+	}
+
+	if _, exists := object[key]; !exists {
+		// Settle on the current order before extending it
+		v.KeyOrder = append(v.orderedKeys(), key)
+	}
+	object[key] = value
+
+	return nil
+}
+
+// Append adds an element to the end of an array
+func (v *JSONValue) Append(value JSONValue) error {
+	if v.Kind != KindArray {
+		return typeError(KindArray, *v)
+	}
+	array, _ := v.Value.([]JSONValue)
+	v.Value = append(array, value)
+
+	return nil
+}
+
+// Delete removes a field from an object, reporting whether it was present
+func (v *JSONValue) Delete(key string) bool {
+	if v.Kind != KindObject {
+		return false
+	}
+	object, _ := v.Value.(map[string]JSONValue)
+	if _, exists := object[key]; !exists {
+		return false
+	}
+
+	// Settle on the current order before removing the key from it
+	keyOrder := v.orderedKeys()
+	v.KeyOrder = slices.DeleteFunc(keyOrder, func(k string) bool { return k == key })
+	delete(object, key)
+
+	return true
+}
+
+// Merge deep merges two objects into a new one: fields of override replace those of
+// base, except that objects found in both are merged recursively. Arrays and other
+// values are replaced as a whole. Keys only found in override follow those of base.
+func Merge(base, override JSONValue) (JSONValue, error) {
+	if base.Kind != KindObject {
+		return JSONValue{}, typeError(KindObject, base)
+	}
+	if override.Kind != KindObject {
+		return JSONValue{}, typeError(KindObject, override)
+	}
+
+	return merge(base, override), nil
+}
+
+// merge combines two objects for Merge, sharing nothing with either of them
+func merge(base, override JSONValue) JSONValue {
+	baseFields, _ := base.Value.(map[string]JSONValue)
+	overrideFields, _ := override.Value.(map[string]JSONValue)
+	object := make(map[string]JSONValue, len(baseFields)+len(overrideFields))
+	keyOrder := make([]string, 0, len(baseFields)+len(overrideFields))
+
+	for _, key := range base.orderedKeys() {
+		field, overridden := overrideFields[key]
+		switch {
+		case !overridden:
+			field = baseFields[key].Clone()
+		case field.Kind == KindObject && baseFields[key].Kind == KindObject:
+			field = merge(baseFields[key], field)
+		default:
+			field = field.Clone()
+		}
+		object[key] = field
+		keyOrder = append(keyOrder, key)
+	}
+	for _, key := range override.orderedKeys() {
+		if _, exists := object[key]; !exists {
+			object[key] = overrideFields[key].Clone()
+			keyOrder = append(keyOrder, key)
+		}
+	}
+
+	return JSONValue{Kind: KindObject, Value: object, KeyOrder: keyOrder}
+}
@@ -0,0 +1,144 @@
+package jsonparser
+
+import "testing"
+
+func TestSet(t *testing.T) {
+	value := mustParse(t, `{"b": 1, "a": 2}`)
+	if err := value.Set("c", JSONValue{Kind: KindString, Value: "new"}); err != nil {
+		t.Fatalf("Set(c) error = %v", err)
+	}
+	if err := value.Set("b", JSONValue{Kind: KindBool, Value: true}); err != nil {
+		t.Fatalf("Set(b) error = %v", err)
+	}
+	if got, want := value.String(), `{"b":true,"a":2,"c":"new"}`; got != want {
+		t.Errorf("after Set got %s, want %s", got, want)
+	}
+
+	array := mustParse(t, `[1]`)
+	if err := array.Set("a", JSONValue{}); err == nil || err.Error() != "expected object, got array" {
+		t.Errorf("Set on an array: got error %v", err)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`[]`, `[1,"x"]`},
+		{`[true, [0]]`, `[true,[0],1,"x"]`},
+	}
+	for _, tt := range tests {
+		value := mustParse(t, tt.input)
+		for _, element := range []JSONValue{{Kind: KindNumber, Value: 1.0}, {Kind: KindString, Value: "x"}} {
+			if err := value.Append(element); err != nil {
+				t.Fatalf("Append() error = %v", err)
+			}
+		}
+		if got := value.String(); got != tt.want {
+			t.Errorf("Append to %s got %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	object := mustParse(t, `{}`)
+	if err := object.Append(JSONValue{}); err == nil || err.Error() != "expected array, got object" {
+		t.Errorf("Append on an object: got error %v", err)
+	}
+}
+
+func TestAppendToCopy(t *testing.T) {
+	// Copies of a parsed array must not share room to grow, or appending to one
+	// would overwrite what was appended to the other
+	for _, iterative := range []bool{false, true} {
+		value, err := parseWith(`[1, 2, 3]`, iterative)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		other := value
+		if err := other.Append(JSONValue{Kind: KindString, Value: "other"}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if err := value.Append(JSONValue{Kind: KindString, Value: "value"}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if got, want := other.String(), `[1,2,3,"other"]`; got != want {
+			t.Errorf("iterative %v: copy got %s, want %s", iterative, got, want)
+		}
+		if got, want := value.String(), `[1,2,3,"value"]`; got != want {
+			t.Errorf("iterative %v: original got %s, want %s", iterative, got, want)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	value := mustParse(t, `{"a": 1, "b": 2, "c": 3}`)
+	if !value.Delete("b") {
+		t.Error("Delete(b) = false, want true")
+	}
+	if value.Delete("missing") {
+		t.Error("Delete(missing) = true, want false")
+	}
+	if got, want := value.String(), `{"a":1,"c":3}`; got != want {
+		t.Errorf("after Delete got %s, want %s", got, want)
+	}
+	// A key added after a deletion goes last
+	if err := value.Set("b", JSONValue{Kind: KindNull}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := value.String(), `{"a":1,"c":3,"b":null}`; got != want {
+		t.Errorf("after Set got %s, want %s", got, want)
+	}
+
+	array := mustParse(t, `["a"]`)
+	if array.Delete("a") {
+		t.Error("Delete on an array = true, want false")
+	}
+}
+
+func TestMergeNested(t *testing.T) {
+	base := mustParse(t, `{"db": {"host": "localhost", "port": 5432, "opts": {"ssl": false}}, "debug": false, "tags": ["a", "b"]}`)
+	override := mustParse(t, `{"db": {"port": 6543, "opts": {"timeout": 5}}, "tags": ["c"], "name": "prod"}`)
+	merged, err := Merge(base, override)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"db":{"host":"localhost","port":6543,"opts":{"ssl":false,"timeout":5}},"debug":false,"tags":["c"],"name":"prod"}`
+	if got := merged.String(); got != want {
+		t.Errorf("Merge() = %s, want %s", got, want)
+	}
+
+	// The merged tree shares nothing with its inputs
+	db, _ := merged.Get("db")
+	if err := db.Set("host", JSONValue{Kind: KindNull}); err != nil {
+		t.Fatal(err)
+	}
+	if host, _ := base.Path("db.host"); host.Value != "localhost" {
+		t.Errorf("changing the merged tree changed base db.host to %v", host.Value)
+	}
+}
+
+func TestMergeScalarOverride(t *testing.T) {
+	tests := []struct {
+		base, override string
+		want           string
+	}{
+		{`{"a": 1}`, `{"a": "x"}`, `{"a":"x"}`},
+		{`{"a": {"b": 1}}`, `{"a": 2}`, `{"a":2}`},
+		{`{"a": 2}`, `{"a": {"b": 1}}`, `{"a":{"b":1}}`},
+		{`{"a": 1}`, `{"a": null}`, `{"a":null}`},
+		{`{"a": 1}`, `{}`, `{"a":1}`},
+	}
+	for _, tt := range tests {
+		merged, err := Merge(mustParse(t, tt.base), mustParse(t, tt.override))
+		if err != nil || merged.String() != tt.want {
+			t.Errorf("Merge(%s, %s) = %s, %v, want %s", tt.base, tt.override, merged, err, tt.want)
+		}
+	}
+
+	if _, err := Merge(mustParse(t, `[1]`), mustParse(t, `{}`)); err == nil || err.Error() != "expected object, got array" {
+		t.Errorf("Merge() of an array base: got error %v", err)
+	}
+	if _, err := Merge(mustParse(t, `{}`), mustParse(t, `"x"`)); err == nil || err.Error() != "expected object, got string" {
+		t.Errorf("Merge() of a string override: got error %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package jsonparser
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// Number is a JSON number kept in its original textual form, which is what
+// JSONParser.UseNumber stores instead of float64
+type Number string
+
+// String returns the number's original text
+func (n Number) String() string {
+	return string(n)
+}
+
+// Float64 returns the number as a float64, rounding it to the nearest representable value.
+// Numbers too large for a float64 return an infinity and an error wrapping strconv.ErrRange.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// Int64 returns the number as an int64, failing for fractions and out of range values
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// BigFloat returns the number as a big.Float with enough precision to keep every digit
+func (n Number) BigFloat() (*big.Float, error) {
+	return n.bigFloat(n.precision())
+}
+
+// precision returns the mantissa bits needed to keep every digit of the number
+func (n Number) precision() uint {
+	// Each decimal digit needs a little under 4 bits of mantissa
+	return max(uint(len(n))*4, 64)
+}
+
+// bigFloat returns the number as a big.Float rounded to prec bits
+func (n Number) bigFloat(prec uint) (*big.Float, error) {
+	f, _, err := big.ParseFloat(string(n), 10, prec, big.ToNearestEven)
+
+	return f, err
+}
+
+// equalNumbers reports whether two numbers have the same value. Both are rounded
+// to the same precision, so that for example 0.1 and 0.10 compare equal.
+func equalNumbers(a, b Number) (equal bool, ok bool) {
+	prec := max(a.precision(), b.precision())
+	x, errA := a.bigFloat(prec)
+	y, errB := b.bigFloat(prec)
+	if errA != nil || errB != nil {
+		return false, false
+	}
+
+	return x.Cmp(y) == 0, true
+}
@@ -0,0 +1,110 @@
+package jsonparser
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// parseNumbers parses input with UseNumber set
+func parseNumbers(t *testing.T, input string) JSONValue {
+	t.Helper()
+	p := NewJSONParser([]byte(input))
+	p.UseNumber = true
+	value, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+
+	return value
+}
+
+func TestNumberLargeInteger(t *testing.T) {
+	n := parseNumbers(t, `9007199254740993`).Value.(Number)
+	if got := n.String(); got != "9007199254740993" {
+		t.Errorf("String() = %s, want 9007199254740993", got)
+	}
+	if got, err := n.Int64(); err != nil || got != 9007199254740993 {
+		t.Errorf("Int64() = %d, %v, want 9007199254740993", got, err)
+	}
+	// float64 rounds to the nearest even value
+	if got, err := n.Float64(); err != nil || got != 9007199254740992 {
+		t.Errorf("Float64() = %v, %v, want 9007199254740992", got, err)
+	}
+	if f, err := n.BigFloat(); err != nil || f.Text('f', 0) != "9007199254740993" {
+		t.Errorf("BigFloat() = %v, %v, want 9007199254740993", f, err)
+	}
+
+	if _, err := Number("92233720368547758070").Int64(); err == nil {
+		t.Error("Int64() of an out of range number succeeded")
+	}
+}
+
+func TestNumberHighPrecisionDecimal(t *testing.T) {
+	const digits = "3.14159265358979323846264338327950288"
+	n := parseNumbers(t, digits).Value.(Number)
+	if got := n.String(); got != digits {
+		t.Errorf("String() = %s, want %s", got, digits)
+	}
+	if f, err := n.BigFloat(); err != nil || f.Text('f', 35) != digits {
+		t.Errorf("BigFloat() = %v, %v, want %s", f, err, digits)
+	}
+}
+
+func TestEqualNumbers(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{`9007199254740993`, `9007199254740992`, false},
+		{`9007199254740993`, `9007199254740993`, true},
+		{`0.10000000000000000001`, `0.1`, false},
+		{`0.1`, `0.10000000000000000000`, true},
+		{`1e2`, `100.0`, true},
+		{`[1, 2.50]`, `[1.0, 2.5]`, true},
+	}
+	for _, tt := range tests {
+		a, b := parseNumbers(t, tt.a), parseNumbers(t, tt.b)
+		if got := a.Equal(b); got != tt.want {
+			t.Errorf("%s.Equal(%s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	// A Number and a float64 compare as float64
+	if !parseNumbers(t, `9007199254740993`).Equal(mustParse(t, `9007199254740992`)) {
+		t.Error("Number 9007199254740993 does not equal float64 9007199254740992")
+	}
+}
+
+func TestDiffNumbers(t *testing.T) {
+	a := parseNumbers(t, `{"id": 9007199254740993, "n": 1.0}`)
+	b := parseNumbers(t, `{"id": 9007199254740992, "n": 1}`)
+	entries := Diff(a, b)
+	if len(entries) != 1 || entries[0].Path != "id" || entries[0].Kind != DiffChanged {
+		t.Errorf("Diff() = %v, want id changed", entries)
+	}
+}
+
+func TestNumberOverflow(t *testing.T) {
+	for _, input := range []string{`1e1000`, `-1e1000`} {
+		want := "number " + input + " overflows float64, UseNumber keeps its text at offset 0 (line 1, column 1)"
+		if got := parseError(t, input); got != want {
+			t.Errorf("Parse(%s) error = %q, want %q", input, got, want)
+		}
+	}
+
+	// UseNumber keeps the text, and converting it reports the overflow
+	value := parseNumbers(t, `[1e1000]`)
+	if got := value.String(); got != `[1e1000]` {
+		t.Errorf("UseNumber: Parse([1e1000]) = %s, want [1e1000]", got)
+	}
+	element, _ := value.Index(0)
+	if _, err := element.AsFloat64(); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("AsFloat64() error = %v, want strconv.ErrRange", err)
+	}
+
+	// Underflow rounds to zero like encoding/json
+	if got := mustParse(t, `1e-1000`).Value; got != 0.0 {
+		t.Errorf("Parse(1e-1000) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,192 @@
+package jsonparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a single step of a Path lookup, either an object key or an array index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// String renders the segment the way it is written in a path
+func (s pathSegment) String() string {
+	if s.isIndex {
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	if needsQuoting(s.key) {
+		return "[" + strconv.Quote(s.key) + "]"
+	}
+
+	return s.key
+}
+
+// needsQuoting reports whether a key can only be written in a path as a quoted
+// segment, because it is empty or holds a '.' or '['
+func needsQuoting(key string) bool {
+	return key == "" || strings.ContainsAny(key, ".[")
+}
+
+// Path looks up a nested value using a dotted path with bracketed array indices,
+// such as "address.city" or "tags[3][0]". A key that is empty or holds a '.' or '['
+// is written as a bracketed Go string literal, as in `files["a.txt"].size`.
+func (v JSONValue) Path(path string) (JSONValue, error) {
+	segments, err := splitPath(path)
+	if err != nil {
+		return JSONValue{}, err
+	}
+
+	current, missing, ok := v.follow(segments)
+	if !ok {
+		return JSONValue{}, fmt.Errorf("path %q: segment %q not found", path, missing)
+	}
+
+	return current, nil
+}
+
+// Exists reports whether a Path lookup would find a value
+func (v JSONValue) Exists(path string) bool {
+	segments, err := splitPath(path)
+	if err != nil {
+		return false
+	}
+	_, _, ok := v.follow(segments)
+
+	return ok
+}
+
+// follow walks the segments from v, returning the first segment that could not be found if any
+func (v JSONValue) follow(segments []pathSegment) (JSONValue, pathSegment, bool) {
+	current := v
+	for _, segment := range segments {
+		var ok bool
+		if segment.isIndex {
+			current, ok = current.Index(segment.index)
+		} else {
+			current, ok = current.Get(segment.key)
+		}
+		if !ok {
+			return JSONValue{}, segment, false
+		}
+	}
+
+	return current, pathSegment{}, true
+}
+
+// splitPath breaks a path into its key and index segments
+func splitPath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	rest := path
+	for rest != "" {
+		switch rest[0] {
+		case '[':
+			if strings.HasPrefix(rest, `["`) {
+				quoted, err := strconv.QuotedPrefix(rest[1:])
+				if err != nil || !strings.HasPrefix(rest[1+len(quoted):], "]") {
+					return nil, fmt.Errorf("path %q: invalid quoted key in %q", path, rest)
+				}
+				key, _ := strconv.Unquote(quoted)
+				segments = append(segments, pathSegment{key: key})
+				rest = rest[len(quoted)+2:]
+				continue
+			}
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q: unterminated index", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path %q: invalid index %q", path, rest[:end+1])
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		case '.':
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("path %q: empty key", path)
+			}
+			rest = rest[1:]
+			fallthrough
+		default:
+			// A key may only start the path or follow a '.'
+			if len(segments) > 0 && path[len(path)-len(rest)-1] != '.' {
+				return nil, fmt.Errorf("path %q: expected '.' or '[' before %q", path, rest)
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("path %q: empty key", path)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+
+	return segments, nil
+}
+
+// Pointer looks up a nested value using an RFC 6901 JSON Pointer such as
+// "/address/city" or "/tags/3/0". The empty pointer refers to the value itself.
+func (v JSONValue) Pointer(ptr string) (JSONValue, error) {
+	if ptr == "" {
+		return v, nil
+	}
+	if ptr[0] != '/' {
+		return JSONValue{}, fmt.Errorf("pointer %q: must be empty or start with '/'", ptr)
+	}
+
+	current := v
+	for _, token := range strings.Split(ptr[1:], "/") {
+		segment, err := unescapePointerToken(token)
+		if err != nil {
+			return JSONValue{}, fmt.Errorf("pointer %q: %w", ptr, err)
+		}
+
+		// Arrays are indexed by decimal numbers without leading zeros,
+		// any other value is looked up as an object key
+		ok := false
+		if current.Kind == KindArray {
+			if index, err := strconv.Atoi(segment); err == nil && index >= 0 && strconv.Itoa(index) == segment {
+				current, ok = current.Index(index)
+			}
+		} else {
+			current, ok = current.Get(segment)
+		}
+		if !ok {
+			return JSONValue{}, fmt.Errorf("pointer %q: segment %q not found", ptr, token)
+		}
+	}
+
+	return current, nil
+}
+
+// unescapePointerToken decodes the ~1 and ~0 escapes of a JSON Pointer reference token
+func unescapePointerToken(token string) (string, error) {
+	if !strings.Contains(token, "~") {
+		return token, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(token); i++ {
+		if token[i] != '~' {
+			b.WriteByte(token[i])
+			continue
+		}
+		if i+1 == len(token) || (token[i+1] != '0' && token[i+1] != '1') {
+			return "", fmt.Errorf("invalid escape in segment %q", token)
+		}
+		if token[i+1] == '0' {
+			b.WriteByte('~')
+		} else {
+			b.WriteByte('/')
+		}
+		i++
+	}
+
+	return b.String(), nil
+}
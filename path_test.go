@@ -0,0 +1,176 @@
+package jsonparser
+
+import "testing"
+
+func TestPath(t *testing.T) {
+	doc := mustParse(t, sampleDocument)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"name", `"John Doe"`},
+		{"address.city", `"New York"`},
+		{"tags[0]", `"golang"`},
+		{"tags[3][1]", `2`},
+		{"tags[3]", `[1,2,3]`},
+	}
+	for _, tt := range tests {
+		value, err := doc.Path(tt.path)
+		if err != nil {
+			t.Errorf("Path(%q): %v", tt.path, err)
+			continue
+		}
+		if got := value.String(); got != tt.want {
+			t.Errorf("Path(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+
+	errorTests := []struct {
+		path string
+		want string
+	}{
+		{"address.country", `path "address.country": segment "country" not found`},
+		{"tags[4]", `path "tags[4]": segment "[4]" not found`},
+		{"tags[3][0][0]", `path "tags[3][0][0]": segment "[0]" not found`},
+		{"name.first", `path "name.first": segment "first" not found`},
+		{"tags[x]", `path "tags[x]": invalid index "[x]"`},
+		{"tags[1", `path "tags[1": unterminated index`},
+	}
+	for _, tt := range errorTests {
+		if _, err := doc.Path(tt.path); err == nil || err.Error() != tt.want {
+			t.Errorf("Path(%q) error = %v, want %q", tt.path, err, tt.want)
+		}
+	}
+}
+
+// rfc6901Document is the example document of RFC 6901, section 5
+const rfc6901Document = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8
+}`
+
+func TestPointerRFC6901Examples(t *testing.T) {
+	doc := mustParse(t, rfc6901Document)
+	tests := []struct {
+		ptr  string
+		want string
+	}{
+		{``, doc.String()},
+		{`/foo`, `["bar","baz"]`},
+		{`/foo/0`, `"bar"`},
+		{`/`, `0`},
+		{`/a~1b`, `1`},
+		{`/c%d`, `2`},
+		{`/e^f`, `3`},
+		{`/g|h`, `4`},
+		{`/i\j`, `5`},
+		{`/k"l`, `6`},
+		{`/ `, `7`},
+		{`/m~0n`, `8`},
+	}
+	for _, tt := range tests {
+		got, err := doc.Pointer(tt.ptr)
+		if err != nil {
+			t.Errorf("Pointer(%q) error = %v", tt.ptr, err)
+		} else if got.String() != tt.want {
+			t.Errorf("Pointer(%q) = %s, want %s", tt.ptr, got, tt.want)
+		}
+	}
+}
+
+func TestPointerErrors(t *testing.T) {
+	doc := mustParse(t, sampleDocument)
+	if got, err := doc.Pointer("/tags/3/0"); err != nil || got.String() != "1" {
+		t.Errorf("Pointer(/tags/3/0) = %s, %v, want 1", got, err)
+	}
+
+	tests := []struct {
+		ptr string
+		err string
+	}{
+		{`/tags/4`, `pointer "/tags/4": segment "4" not found`},
+		{`/tags/01`, `pointer "/tags/01": segment "01" not found`},
+		{`/tags/-1`, `pointer "/tags/-1": segment "-1" not found`},
+		{`/address/country`, `pointer "/address/country": segment "country" not found`},
+		{`/name/first`, `pointer "/name/first": segment "first" not found`},
+		{`/a~2b`, `pointer "/a~2b": invalid escape in segment "a~2b"`},
+		{`address`, `pointer "address": must be empty or start with '/'`},
+	}
+	for _, tt := range tests {
+		if _, err := doc.Pointer(tt.ptr); err == nil || err.Error() != tt.err {
+			t.Errorf("Pointer(%q) error = %v, want %q", tt.ptr, err, tt.err)
+		}
+	}
+}
+
+func TestPathQuotedKeys(t *testing.T) {
+	doc := mustParse(t, `{"a.b": {"c[0]": 1, "": [true]}, "x\"y": 2, "d": {"e": 3}}`)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`["a.b"]`, `{"c[0]":1,"":[true]}`},
+		{`["a.b"]["c[0]"]`, `1`},
+		{`["a.b"][""][0]`, `true`},
+		{`["x\"y"]`, `2`},
+		{`d["e"]`, `3`},
+		{`["d"].e`, `3`},
+	}
+	for _, tt := range tests {
+		value, err := doc.Path(tt.path)
+		if err != nil {
+			t.Errorf("Path(%q): %v", tt.path, err)
+			continue
+		}
+		if got := value.String(); got != tt.want {
+			t.Errorf("Path(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+
+	errorTests := []struct {
+		path string
+		want string
+	}{
+		{`["a.b"]["c"]`, `path "[\"a.b\"][\"c\"]": segment "c" not found`},
+		{`["a.b"][""][1]`, `path "[\"a.b\"][\"\"][1]": segment "[1]" not found`},
+		{`d["f.g"]`, `path "d[\"f.g\"]": segment "[\"f.g\"]" not found`},
+		{`["a.b"`, `path "[\"a.b\"": invalid quoted key in "[\"a.b\""`},
+		{`["a.b]`, `path "[\"a.b]": invalid quoted key in "[\"a.b]"`},
+		{`["a.b"]c`, `path "[\"a.b\"]c": expected '.' or '[' before "c"`},
+	}
+	for _, tt := range errorTests {
+		if _, err := doc.Path(tt.path); err == nil || err.Error() != tt.want {
+			t.Errorf("Path(%q) error = %v, want %q", tt.path, err, tt.want)
+		}
+	}
+}
+
+func TestExists(t *testing.T) {
+	doc := mustParse(t, sampleDocument)
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"name", true},
+		{"address.city", true},
+		{"tags[3][2]", true},
+		{"phone", false},
+		{"address.country", false},
+		{"tags[4]", false},
+		{"name.first", false},
+		{"tags[x]", false},
+	}
+	for _, tt := range tests {
+		if got := doc.Exists(tt.path); got != tt.want {
+			t.Errorf("Exists(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package jsonparser
+
+import "strconv"
+
+// TokenKind identifies the type of a Token
+type TokenKind int
+
+const (
+	TokenEOF TokenKind = iota
+	TokenBeginObject
+	TokenEndObject
+	TokenBeginArray
+	TokenEndArray
+	TokenColon
+	TokenComma
+	TokenString
+	TokenNumber
+	TokenBool
+	TokenNull
+)
+
+// String describes the token kind for use in messages
+func (k TokenKind) String() string {
+	switch k {
+	case TokenEOF:
+		return "end of input"
+	case TokenBeginObject:
+		return "'{'"
+	case TokenEndObject:
+		return "'}'"
+	case TokenBeginArray:
+		return "'['"
+	case TokenEndArray:
+		return "']'"
+	case TokenColon:
+		return "':'"
+	case TokenComma:
+		return "','"
+	case TokenString:
+		return "string"
+	case TokenNumber:
+		return "number"
+	case TokenBool:
+		return "boolean"
+	case TokenNull:
+		return "null"
+	default:
+		return "TokenKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Token is a single lexical element of a JSON document
+type Token struct {
+	Kind TokenKind
+	// Value is the decoded contents of a string, the text of a number or
+	// literal, or the delimiter itself
+	Value  string
+	Offset int // byte offset of the token in the input
+}
+
+// delimiterKinds maps each delimiter to its token kind
+var delimiterKinds = map[string]TokenKind{
+	"{": TokenBeginObject,
+	"}": TokenEndObject,
+	"[": TokenBeginArray,
+	"]": TokenEndArray,
+	":": TokenColon,
+	",": TokenComma,
+}
+
+// Tokenizer splits a JSON document into tokens without checking how they are arranged
+type Tokenizer struct {
+	parser *JSONParser
+}
+
+// NewTokenizer creates a new Tokenizer reading the tokens of data
+func NewTokenizer(data []byte) *Tokenizer {
+	return &Tokenizer{parser: NewJSONParser(data)}
+}
+
+// Next returns the next token, or a TokenEOF token once the input is exhausted
+func (t *Tokenizer) Next() (Token, error) {
+	p := t.parser
+	if err := p.skipWhitespaces(); err != nil {
+		return Token{}, err
+	}
+	if p.buffered(1) == 0 {
+		return Token{Kind: TokenEOF, Offset: p.offset}, nil
+	}
+	if err := p.readNextToken(); err != nil {
+		return Token{}, err
+	}
+
+	token := Token{Value: p.currentToken, Offset: p.tokenOffset}
+	switch p.currentKind {
+	case tokenDelim:
+		token.Kind = delimiterKinds[p.currentToken]
+	case tokenLiteral:
+		token.Kind = TokenBool
+		if p.currentToken == "null" {
+			token.Kind = TokenNull
+		}
+	case tokenString:
+		token.Kind = TokenString
+	case tokenNumber:
+		token.Kind = TokenNumber
+	}
+
+	return token, nil
+}
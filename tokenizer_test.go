@@ -0,0 +1,106 @@
+package jsonparser
+
+import (
+	"strings"
+	"testing"
+)
+
+// tokenize returns every token of input up to the end, formatted as kind or kind:value
+func tokenize(t *testing.T, input string) string {
+	t.Helper()
+	tokenizer := NewTokenizer([]byte(input))
+	var tokens []string
+	for {
+		token, err := tokenizer.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		switch token.Kind {
+		case TokenEOF:
+			return strings.Join(tokens, " ")
+		case TokenString, TokenNumber, TokenBool:
+			tokens = append(tokens, token.Kind.String()+":"+token.Value)
+		default:
+			tokens = append(tokens, token.Kind.String())
+		}
+	}
+}
+
+func TestTokenizeSampleDocument(t *testing.T) {
+	want := strings.Join([]string{
+		"'{'",
+		"string:name ':' string:John Doe ','",
+		"string:age ':' number:30 ','",
+		"string:email ':' string:john.doe@example.com ','",
+		"string:active ':' boolean:true ','",
+		"string:address ':' '{' string:city ':' string:New York ',' string:zip ':' string:10001 '}' ','",
+		"string:tags ':' '[' string:golang ',' string:json ',' string:parser ','",
+		"'[' number:1 ',' number:2 ',' number:3 ']' ']'",
+		"'}'",
+	}, " ")
+	if got := tokenize(t, sampleDocument); got != want {
+		t.Errorf("tokens:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestTokenizerOffsetsAndErrors(t *testing.T) {
+	tokenizer := NewTokenizer([]byte(` ["a\n", null] `))
+	for _, want := range []Token{
+		{TokenBeginArray, "[", 1},
+		{TokenString, "a\n", 2},
+		{TokenComma, ",", 7},
+		{TokenNull, "null", 9},
+		{TokenEndArray, "]", 13},
+		{TokenEOF, "", 15},
+	} {
+		if got, err := tokenizer.Next(); err != nil || got != want {
+			t.Errorf("Next() = %+v, %v, want %+v", got, err, want)
+		}
+	}
+
+	// Tokens are not checked against each other, only on their own
+	if got, want := tokenize(t, `]] : {`), "']' ']' ':' '{'"; got != want {
+		t.Errorf("tokens = %s, want %s", got, want)
+	}
+	_, err := NewTokenizer([]byte(`@`)).Next()
+	if want := "unexpected character '@' at offset 0 (line 1, column 1)"; err == nil || err.Error() != want {
+		t.Errorf("Next() error = %v, want %q", err, want)
+	}
+}
+
+func TestTokensAreExact(t *testing.T) {
+	input := `{"key": "va\"lue", "n": -12.5e+3, "u": "éx", "e": "", "z": 0}`
+	tokenizer := NewTokenizer([]byte(input))
+	want := []string{"{", "key", ":", `va"lue`, ",", "n", ":", "-12.5e+3", ",", "u", ":", "éx", ",", "e", ":", "", ",", "z", ":", "0", "}"}
+	for _, w := range want {
+		token, err := tokenizer.Next()
+		if err != nil || token.Value != w {
+			t.Fatalf("Next() = %q, %v, want %q", token.Value, err, w)
+		}
+		// Delimiters, numbers and literals are their own text in the input
+		if token.Kind != TokenString && input[token.Offset:token.Offset+len(w)] != w {
+			t.Errorf("token %q at offset %d does not match the input", w, token.Offset)
+		}
+	}
+	if token, err := tokenizer.Next(); err != nil || token.Kind != TokenEOF {
+		t.Errorf("Next() at end = %+v, %v, want TokenEOF", token, err)
+	}
+}
+
+func BenchmarkTokenize(b *testing.B) {
+	input := []byte(strings.Repeat(sampleDocument, 100))
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tokenizer := NewTokenizer(input)
+		for {
+			token, err := tokenizer.Next()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if token.Kind == TokenEOF {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package jsonparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	numberType     = reflect.TypeOf(Number(""))
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+)
+
+// Unmarshal parses the JSON data and stores the result in the value pointed to by v
+func Unmarshal(data []byte, v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return errors.New("unmarshal: target must be a non-nil pointer")
+	}
+
+	// Numbers keep their text so each target converts them without going through float64
+	parser := NewJSONParser(data)
+	parser.UseNumber = true
+	value, err := parser.Parse()
+	if err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return decodeValue(value, target.Elem())
+}
+
+// decodeValue stores a parsed value into the Go value held by target
+func decodeValue(value JSONValue, target reflect.Value) error {
+	// null resets pointers, maps, slices and interfaces and leaves anything else untouched
+	if value.Kind == KindNull {
+		switch target.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			target.Set(reflect.Zero(target.Type()))
+		}
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decodeValue(value, target.Elem())
+	case reflect.Interface:
+		if target.NumMethod() != 0 {
+			return decodeError(value, target)
+		}
+		plain, err := decodeInterface(value)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(plain))
+		return nil
+	}
+
+	switch value.Kind {
+	case KindBool:
+		if target.Kind() != reflect.Bool {
+			return decodeError(value, target)
+		}
+		target.SetBool(value.Value.(bool))
+	case KindString:
+		if target.Kind() != reflect.String {
+			return decodeError(value, target)
+		}
+		target.SetString(value.Value.(string))
+	case KindNumber:
+		return decodeNumber(value, target)
+	case KindArray:
+		return decodeArray(value, target)
+	case KindObject:
+		return decodeObject(value, target)
+	default:
+		return decodeError(value, target)
+	}
+
+	return nil
+}
+
+// decodeNumber stores a number into an integer, floating point, Number or json.Number target
+func decodeNumber(value JSONValue, target reflect.Value) error {
+	num, _ := value.AsNumber()
+	if target.Type() == numberType || target.Type() == jsonNumberType {
+		target.SetString(string(num))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := num.Int64()
+		if err != nil || target.OverflowInt(i) {
+			return numberError(num, target)
+		}
+		target.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(string(num), 10, 64)
+		if err != nil || target.OverflowUint(u) {
+			return numberError(num, target)
+		}
+		target.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := num.Float64()
+		if err != nil || target.OverflowFloat(f) {
+			return numberError(num, target)
+		}
+		target.SetFloat(f)
+	default:
+		return decodeError(value, target)
+	}
+
+	return nil
+}
+
+// decodeInterface converts a value for an empty interface target, with numbers
+// as float64 like encoding/json
+func decodeInterface(value JSONValue) (interface{}, error) {
+	switch value.Kind {
+	case KindNumber:
+		num, _ := value.AsNumber()
+		f, err := num.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal: number %s does not fit into Go value of type float64", num)
+		}
+		return f, nil
+	case KindArray:
+		elements := value.Value.([]JSONValue)
+		array := make([]interface{}, len(elements))
+		for i, element := range elements {
+			plain, err := decodeInterface(element)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = plain
+		}
+		return array, nil
+	case KindObject:
+		fields := value.Value.(map[string]JSONValue)
+		object := make(map[string]interface{}, len(fields))
+		for key, field := range fields {
+			plain, err := decodeInterface(field)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = plain
+		}
+		return object, nil
+	default:
+		return value.Value, nil
+	}
+}
+
+// decodeArray stores an array into a slice or fixed size array target
+func decodeArray(value JSONValue, target reflect.Value) error {
+	elements := value.Value.([]JSONValue)
+
+	switch target.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(target.Type(), len(elements), len(elements))
+		for i, element := range elements {
+			if err := decodeValue(element, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+	case reflect.Array:
+		for i := 0; i < target.Len(); i++ {
+			if i >= len(elements) {
+				target.Index(i).Set(reflect.Zero(target.Type().Elem()))
+				continue
+			}
+			if err := decodeValue(elements[i], target.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return decodeError(value, target)
+	}
+
+	return nil
+}
+
+// decodeObject stores an object into a struct or string keyed map target
+func decodeObject(value JSONValue, target reflect.Value) error {
+	object := value.Value.(map[string]JSONValue)
+
+	switch target.Kind() {
+	case reflect.Map:
+		if target.Type().Key().Kind() != reflect.String {
+			return decodeError(value, target)
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMapWithSize(target.Type(), len(object)))
+		}
+		for key, field := range object {
+			element := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeValue(field, element); err != nil {
+				return err
+			}
+			target.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), element)
+		}
+	case reflect.Struct:
+		for key, field := range object {
+			fieldValue, ok := structField(target, key)
+			if !ok {
+				continue
+			}
+			if err := decodeValue(field, fieldValue); err != nil {
+				return err
+			}
+		}
+	default:
+		return decodeError(value, target)
+	}
+
+	return nil
+}
+
+// structField finds the struct field matching an object key, honoring `json:"..."` tags
+func structField(target reflect.Value, key string) (reflect.Value, bool) {
+	var fallback reflect.Value
+	structType := target.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		// Exact matches win, otherwise fall back to a case-insensitive match like encoding/json
+		if name == key {
+			return target.Field(i), true
+		}
+		if !fallback.IsValid() && strings.EqualFold(name, key) {
+			fallback = target.Field(i)
+		}
+	}
+
+	return fallback, fallback.IsValid()
+}
+
+// ToInterface converts a parsed value into the plain Go types used by encoding/json:
+// map[string]interface{}, []interface{}, float64, string, bool and nil.
+// Numbers parsed with UseNumber become json.Number.
+func ToInterface(value JSONValue) interface{} {
+	switch value.Kind {
+	case KindNumber:
+		if num, ok := value.Value.(Number); ok {
+			return json.Number(num)
+		}
+		return value.Value
+	case KindArray:
+		elements := value.Value.([]JSONValue)
+		array := make([]interface{}, len(elements))
+		for i, element := range elements {
+			array[i] = ToInterface(element)
+		}
+		return array
+	case KindObject:
+		fields := value.Value.(map[string]JSONValue)
+		object := make(map[string]interface{}, len(fields))
+		for key, field := range fields {
+			object[key] = ToInterface(field)
+		}
+		return object
+	default:
+		return value.Value
+	}
+}
+
+// FromInterface builds a JSONValue from Go values: nil, booleans, strings, numbers,
+// Number and json.Number, slices and arrays, string keyed maps, and pointers to them.
+// Integers are kept as a Number so that they stay exact. Object keys are ordered alphabetically.
+func FromInterface(v interface{}) (JSONValue, error) {
+	if value, ok := v.(JSONValue); ok {
+		return value, nil
+	}
+
+	return fromValue(reflect.ValueOf(v))
+}
+
+// fromValue builds a JSONValue from the Go value held by source
+func fromValue(source reflect.Value) (JSONValue, error) {
+	if !source.IsValid() {
+		return JSONValue{Kind: KindNull}, nil
+	}
+
+	switch source.Type() {
+	case numberType, jsonNumberType:
+		num := Number(source.String())
+		if !isValidNumber([]byte(num)) {
+			return JSONValue{}, fmt.Errorf("from interface: invalid number %q", num)
+		}
+		return JSONValue{Kind: KindNumber, Value: num}, nil
+	}
+
+	switch source.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if source.IsNil() {
+			return JSONValue{Kind: KindNull}, nil
+		}
+		return fromValue(source.Elem())
+	case reflect.Bool:
+		return JSONValue{Kind: KindBool, Value: source.Bool()}, nil
+	case reflect.String:
+		return JSONValue{Kind: KindString, Value: source.String()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return JSONValue{Kind: KindNumber, Value: Number(strconv.FormatInt(source.Int(), 10))}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return JSONValue{Kind: KindNumber, Value: Number(strconv.FormatUint(source.Uint(), 10))}, nil
+	case reflect.Float32, reflect.Float64:
+		return JSONValue{Kind: KindNumber, Value: source.Float()}, nil
+	case reflect.Slice, reflect.Array:
+		if source.Kind() == reflect.Slice && source.IsNil() {
+			return JSONValue{Kind: KindNull}, nil
+		}
+		array := make([]JSONValue, source.Len())
+		for i := range array {
+			element, err := fromValue(source.Index(i))
+			if err != nil {
+				return JSONValue{}, err
+			}
+			array[i] = element
+		}
+		return JSONValue{Kind: KindArray, Value: array}, nil
+	case reflect.Map:
+		if source.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if source.IsNil() {
+			return JSONValue{Kind: KindNull}, nil
+		}
+		object := make(map[string]JSONValue, source.Len())
+		keyOrder := make([]string, 0, source.Len())
+		iter := source.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			field, err := fromValue(iter.Value())
+			if err != nil {
+				return JSONValue{}, err
+			}
+			object[key] = field
+			keyOrder = append(keyOrder, key)
+		}
+		sort.Strings(keyOrder)
+		return JSONValue{Kind: KindObject, Value: object, KeyOrder: keyOrder}, nil
+	}
+
+	return JSONValue{}, fmt.Errorf("from interface: unsupported Go type %s", source.Type())
+}
+
+// numberError reports a number that does not fit into the target's type
+func numberError(num Number, target reflect.Value) error {
+	return fmt.Errorf("unmarshal: number %s does not fit into Go value of type %s", num, target.Type())
+}
+
+// decodeError reports a value that cannot be stored into the target's type
+func decodeError(value JSONValue, target reflect.Value) error {
+	return fmt.Errorf("unmarshal: cannot decode %s into Go value of type %s", value.Kind, target.Type())
+}
@@ -0,0 +1,262 @@
+package jsonparser
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestToInterfaceMatchesEncodingJSON(t *testing.T) {
+	for _, input := range []string{sampleDocument, `[1, "a", null, true, {"b": []}]`, `"s"`, `null`} {
+		var want interface{}
+		if err := json.Unmarshal([]byte(input), &want); err != nil {
+			t.Fatal(err)
+		}
+		if got := ToInterface(mustParse(t, input)); !reflect.DeepEqual(got, want) {
+			t.Errorf("ToInterface(%.20q) = %#v, want %#v", input, got, want)
+		}
+	}
+}
+
+func TestFromInterfaceRoundTrip(t *testing.T) {
+	for _, input := range []string{sampleDocument, `[1.5, "a", null, false, {"b": [{}]}]`, `{}`, `[]`} {
+		want := mustParse(t, input)
+		got, err := FromInterface(ToInterface(want))
+		if err != nil {
+			t.Fatalf("FromInterface(%.20q) error = %v", input, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("FromInterface(%.20q) = %s, want %s", input, got, want)
+		}
+	}
+
+	value, err := FromInterface(map[string]interface{}{"b": []int{1, 2}, "a": "x", "c": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := value.String(), `{"a":"x","b":[1,2],"c":null}`; got != want {
+		t.Errorf("FromInterface() = %s, want %s", got, want)
+	}
+}
+
+func TestFromInterfaceIntegers(t *testing.T) {
+	// Integers past 2^53 must survive being built into a value and decoded again
+	value, err := FromInterface([]interface{}{int64(1<<60 + 1), uint64(math.MaxUint64), int8(-5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := value.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got, want := string(data), `[1152921504606846977,18446744073709551615,-5]`; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+
+	var decoded struct {
+		Signed   int64
+		Unsigned uint64
+	}
+	value, err = FromInterface(map[string]interface{}{"Signed": int64(1<<60 + 1), "Unsigned": uint64(math.MaxUint64)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Unmarshal([]byte(value.String()), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.Signed != 1<<60+1 || decoded.Unsigned != math.MaxUint64 {
+		t.Errorf("round trip got %d and %d", decoded.Signed, decoded.Unsigned)
+	}
+}
+
+func TestFromInterfaceUnsupported(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		err   string
+	}{
+		{make(chan int), "from interface: unsupported Go type chan int"},
+		{map[string]interface{}{"f": func() {}}, "from interface: unsupported Go type func()"},
+		{Number("1.2.3"), `from interface: invalid number "1.2.3"`},
+	}
+	for _, tt := range tests {
+		if _, err := FromInterface(tt.value); err == nil || err.Error() != tt.err {
+			t.Errorf("FromInterface(%T) error = %v, want %q", tt.value, err, tt.err)
+		}
+	}
+}
+
+func TestUnmarshalTypedMaps(t *testing.T) {
+	var counts map[string]int
+	if err := Unmarshal([]byte(`{"a": 1, "b": 2}`), &counts); err != nil {
+		t.Fatalf("Unmarshal() into map[string]int error = %v", err)
+	}
+	if want := map[string]int{"a": 1, "b": 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("Unmarshal() = %v, want %v", counts, want)
+	}
+
+	var lists map[string][]string
+	if err := Unmarshal([]byte(`{"a": ["x", "y"], "b": []}`), &lists); err != nil {
+		t.Fatalf("Unmarshal() into map[string][]string error = %v", err)
+	}
+	if want := map[string][]string{"a": {"x", "y"}, "b": {}}; !reflect.DeepEqual(lists, want) {
+		t.Errorf("Unmarshal() = %v, want %v", lists, want)
+	}
+
+	tests := []struct {
+		input  string
+		target interface{}
+		err    string
+	}{
+		{`{"a": "x"}`, &counts, "unmarshal: cannot decode string into Go value of type int"},
+		{`{"a": [1]}`, &lists, "unmarshal: cannot decode number into Go value of type string"},
+		{`{"1": 2}`, &map[int]int{}, "unmarshal: cannot decode object into Go value of type map[int]int"},
+	}
+	for _, tt := range tests {
+		if err := Unmarshal([]byte(tt.input), tt.target); err == nil || err.Error() != tt.err {
+			t.Errorf("Unmarshal(%s) into %T error = %v, want %q", tt.input, tt.target, err, tt.err)
+		}
+	}
+}
+
+func TestUnmarshalIntegers(t *testing.T) {
+	var signed int64
+	if err := Unmarshal([]byte(`9007199254740993`), &signed); err != nil || signed != 9007199254740993 {
+		t.Errorf("Unmarshal() into int64 = %d, %v, want 9007199254740993", signed, err)
+	}
+	if err := Unmarshal([]byte(`9223372036854775807`), &signed); err != nil || signed != math.MaxInt64 {
+		t.Errorf("Unmarshal() into int64 = %d, %v, want %d", signed, err, int64(math.MaxInt64))
+	}
+	var unsigned uint64
+	if err := Unmarshal([]byte(`18446744073709551615`), &unsigned); err != nil || unsigned != math.MaxUint64 {
+		t.Errorf("Unmarshal() into uint64 = %d, %v, want %d", unsigned, err, uint64(math.MaxUint64))
+	}
+
+	tests := []struct {
+		input  string
+		target interface{}
+		err    string
+	}{
+		{`-1`, new(uint64), "unmarshal: number -1 does not fit into Go value of type uint64"},
+		{`300`, new(int8), "unmarshal: number 300 does not fit into Go value of type int8"},
+		{`1.5`, new(int), "unmarshal: number 1.5 does not fit into Go value of type int"},
+		{`9223372036854775808`, new(int64), "unmarshal: number 9223372036854775808 does not fit into Go value of type int64"},
+		{`1e39`, new(float32), "unmarshal: number 1e39 does not fit into Go value of type float32"},
+		{`1e1000`, new(interface{}), "unmarshal: number 1e1000 does not fit into Go value of type float64"},
+	}
+	for _, tt := range tests {
+		if err := Unmarshal([]byte(tt.input), tt.target); err == nil || err.Error() != tt.err {
+			t.Errorf("Unmarshal(%s) into %T error = %v, want %q", tt.input, tt.target, err, tt.err)
+		}
+	}
+}
+
+func TestUnmarshalInterfaceMatchesEncodingJSON(t *testing.T) {
+	for _, input := range []string{sampleDocument, `[1, 2.5, -3e2, {"a": 9007199254740993}]`} {
+		var got, want interface{}
+		if err := Unmarshal([]byte(input), &got); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(input), &want); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Unmarshal(%.20q) = %#v, want %#v", input, got, want)
+		}
+	}
+}
+
+type address struct {
+	City string
+	Zip  string `json:"zip"`
+}
+
+type person struct {
+	Name    string   `json:"name"`
+	Age     int      `json:"age"`
+	Email   string   `json:"-"`
+	Active  bool     `json:"active,omitempty"`
+	Address address  `json:"address"`
+	Tags    []string `json:"tags"`
+	Home    *address `json:"home"`
+	Score   Number   `json:"score"`
+	secret  string
+}
+
+func TestUnmarshalStruct(t *testing.T) {
+	input := `{
+		"name": "John Doe",
+		"age": 30,
+		"email": "john.doe@example.com",
+		"Email": "ignored",
+		"active": true,
+		"address": {"city": "New York", "zip": "10001"},
+		"tags": ["golang", "json"],
+		"home": {"City": "Boston"},
+		"score": 1.50,
+		"secret": "hidden",
+		"unknown": [1, 2]
+	}`
+	var got person
+	if err := Unmarshal([]byte(input), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := person{
+		Name:    "John Doe",
+		Age:     30,
+		Active:  true,
+		Address: address{City: "New York", Zip: "10001"},
+		Tags:    []string{"golang", "json"},
+		Home:    &address{City: "Boston"},
+		Score:   "1.50",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnmarshalNestedSlices(t *testing.T) {
+	var got struct {
+		Groups [][]address `json:"groups"`
+		Fixed  [2]int      `json:"fixed"`
+	}
+	input := `{"groups": [[{"City": "A"}], [], [{"City": "B"}, {"City": "C"}]], "fixed": [1, 2, 3]}`
+	if err := Unmarshal([]byte(input), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(got.Groups) != 3 || len(got.Groups[1]) != 0 || got.Groups[2][1].City != "C" {
+		t.Errorf("Unmarshal() groups = %+v", got.Groups)
+	}
+	if got.Fixed != [2]int{1, 2} {
+		t.Errorf("Unmarshal() fixed = %v, want [1 2]", got.Fixed)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var p person
+	err := Unmarshal([]byte(`{"name": "x", "age" 30}`), &p)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Offset != 20 {
+		t.Errorf("Unmarshal() error = %v, want a wrapped *SyntaxError at offset 20", err)
+	}
+	if want := "unmarshal: expected ':' but found 30 at offset 20 (line 1, column 21)"; err == nil || err.Error() != want {
+		t.Errorf("Unmarshal() error = %v, want %q", err, want)
+	}
+
+	tests := []struct {
+		input  string
+		target interface{}
+		err    string
+	}{
+		{`{"age": "30"}`, &p, "unmarshal: cannot decode string into Go value of type int"},
+		{`{"address": {"zip": 10001}}`, &p, "unmarshal: cannot decode number into Go value of type string"},
+		{`{}`, p, "unmarshal: target must be a non-nil pointer"},
+		{`{}`, (*person)(nil), "unmarshal: target must be a non-nil pointer"},
+	}
+	for _, tt := range tests {
+		if err := Unmarshal([]byte(tt.input), tt.target); err == nil || err.Error() != tt.err {
+			t.Errorf("Unmarshal(%s) into %T error = %v, want %q", tt.input, tt.target, err, tt.err)
+		}
+	}
+}
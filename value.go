@@ -0,0 +1,78 @@
+package jsonparser
+
+// Equal reports whether two values are deeply equal. Objects compare as
+// unordered sets of keys, arrays compare element by element and numbers
+// compare by numeric value, exactly when both are a Number.
+func (v JSONValue) Equal(other JSONValue) bool {
+	if v.Kind != other.Kind {
+		return false
+	}
+
+	switch v.Kind {
+	case KindNumber:
+		if a, ok := v.Value.(Number); ok {
+			if b, ok := other.Value.(Number); ok {
+				if equal, ok := equalNumbers(a, b); ok {
+					return equal
+				}
+			}
+		}
+		a, errA := v.AsFloat64()
+		b, errB := other.AsFloat64()
+		return errA == nil && errB == nil && a == b
+	case KindArray:
+		a := v.Value.([]JSONValue)
+		b := other.Value.([]JSONValue)
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !a[i].Equal(b[i]) {
+				return false
+			}
+		}
+		return true
+	case KindObject:
+		a := v.Value.(map[string]JSONValue)
+		b := other.Value.(map[string]JSONValue)
+		if len(a) != len(b) {
+			return false
+		}
+		for key, field := range a {
+			otherField, ok := b[key]
+			if !ok || !field.Equal(otherField) {
+				return false
+			}
+		}
+		return true
+	default:
+		return v.Value == other.Value
+	}
+}
+
+// Clone returns a deep copy of the value that shares no objects or arrays with the original
+func (v JSONValue) Clone() JSONValue {
+	switch v.Kind {
+	case KindArray:
+		elements := v.Value.([]JSONValue)
+		array := make([]JSONValue, len(elements))
+		for i, element := range elements {
+			array[i] = element.Clone()
+		}
+		return JSONValue{Kind: v.Kind, Value: array, Start: v.Start, End: v.End, Comments: v.Comments.clone()}
+	case KindObject:
+		fields := v.Value.(map[string]JSONValue)
+		object := make(map[string]JSONValue, len(fields))
+		for key, field := range fields {
+			object[key] = field.Clone()
+		}
+		var keyOrder []string
+		if v.KeyOrder != nil {
+			keyOrder = append(make([]string, 0, len(v.KeyOrder)), v.KeyOrder...)
+		}
+		return JSONValue{Kind: v.Kind, Value: object, KeyOrder: keyOrder, Start: v.Start, End: v.End, Comments: v.Comments.clone()}
+	default:
+		v.Comments = v.Comments.clone()
+		return v
+	}
+}
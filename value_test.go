@@ -0,0 +1,47 @@
+package jsonparser
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{`{"a": 1, "b": [1, 2]}`, `{"b": [1, 2], "a": 1}`, true},
+		{`[1, 2, 3]`, `[3, 2, 1]`, false},
+		{`[1, 2]`, `[1, 2, 3]`, false},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"a": 1}`, `{"b": 1}`, false},
+		{`1.0`, `1`, true},
+		{`1e2`, `100`, true},
+		{`"1"`, `1`, false},
+		{`null`, `null`, true},
+		{`true`, `false`, false},
+	}
+	for _, tt := range tests {
+		a, b := mustParse(t, tt.a), mustParse(t, tt.b)
+		if got := a.Equal(b); got != tt.want {
+			t.Errorf("%s.Equal(%s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	original := mustParse(t, sampleDocument)
+	clone := original.Clone()
+	if !clone.Equal(original) {
+		t.Fatalf("Clone() = %s, want %s", clone, original)
+	}
+
+	address := clone.Value.(map[string]JSONValue)["address"].Value.(map[string]JSONValue)
+	address["city"] = JSONValue{Kind: KindString, Value: "Boston"}
+	tags := clone.Value.(map[string]JSONValue)["tags"].Value.([]JSONValue)
+	tags[0] = JSONValue{Kind: KindNull}
+
+	if city, _ := original.Path("address.city"); city.Value != "New York" {
+		t.Errorf("original address.city = %v after changing the clone", city.Value)
+	}
+	if tag, _ := original.Path("tags[0]"); tag.Value != "golang" {
+		t.Errorf("original tags[0] = %v after changing the clone", tag.Value)
+	}
+}
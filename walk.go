@@ -0,0 +1,99 @@
+package jsonparser
+
+import "strconv"
+
+// Walk calls fn for the value and every value nested in it, depth first with
+// parents before their children. Paths use the syntax accepted by Path, with
+// "" for the value itself. An error from fn stops the walk and is returned.
+func (v JSONValue) Walk(fn func(path string, value JSONValue) error) error {
+	return v.walk("", fn)
+}
+
+// walk visits the value found at path and then its children
+func (v JSONValue) walk(path string, fn func(path string, value JSONValue) error) error {
+	if err := fn(path, v); err != nil {
+		return err
+	}
+
+	switch v.Kind {
+	case KindArray:
+		for i, element := range v.Value.([]JSONValue) {
+			if err := element.walk(indexPath(path, i), fn); err != nil {
+				return err
+			}
+		}
+	case KindObject:
+		object := v.Value.(map[string]JSONValue)
+		for _, key := range v.orderedKeys() {
+			if err := object[key].walk(keyPath(path, key), fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Map returns a new tree in which every value has been replaced by the result
+// of fn, working from parents down to their children. When fn returns false the
+// value is dropped from its array or object, and a dropped root becomes null.
+// Paths are the same as for Walk and refer to the original tree.
+func (v JSONValue) Map(fn func(path string, value JSONValue) (JSONValue, bool)) JSONValue {
+	mapped, ok := v.mapValue("", fn)
+	if !ok {
+		return JSONValue{Kind: KindNull}
+	}
+
+	return mapped
+}
+
+// mapValue transforms the value found at path and then rebuilds its children
+func (v JSONValue) mapValue(path string, fn func(path string, value JSONValue) (JSONValue, bool)) (JSONValue, bool) {
+	v, ok := fn(path, v)
+	if !ok {
+		return JSONValue{}, false
+	}
+
+	switch v.Kind {
+	case KindArray:
+		elements := v.Value.([]JSONValue)
+		array := make([]JSONValue, 0, len(elements))
+		for i, element := range elements {
+			if mapped, ok := element.mapValue(indexPath(path, i), fn); ok {
+				array = append(array, mapped)
+			}
+		}
+		return JSONValue{Kind: KindArray, Value: array}, true
+	case KindObject:
+		fields := v.Value.(map[string]JSONValue)
+		object := make(map[string]JSONValue, len(fields))
+		keyOrder := make([]string, 0, len(fields))
+		for _, key := range v.orderedKeys() {
+			if mapped, ok := fields[key].mapValue(keyPath(path, key), fn); ok {
+				object[key] = mapped
+				keyOrder = append(keyOrder, key)
+			}
+		}
+		return JSONValue{Kind: KindObject, Value: object, KeyOrder: keyOrder}, true
+	default:
+		return v, true
+	}
+}
+
+// keyPath returns the path of an object field below path, quoting keys that Path
+// would otherwise split
+func keyPath(path, key string) string {
+	if needsQuoting(key) {
+		return path + pathSegment{key: key}.String()
+	}
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// indexPath returns the path of an array element below path
+func indexPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,109 @@
+package jsonparser
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWalkCollectsPaths(t *testing.T) {
+	var paths []string
+	err := mustParse(t, sampleDocument).Walk(func(path string, value JSONValue) error {
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"", "name", "age", "email", "active",
+		"address", "address.city", "address.zip",
+		"tags", "tags[0]", "tags[1]", "tags[2]", "tags[3]", "tags[3][0]", "tags[3][1]", "tags[3][2]",
+	}
+	if !slices.Equal(paths, want) {
+		t.Errorf("Walk() paths = %q, want %q", paths, want)
+	}
+}
+
+func TestWalkPathsResolve(t *testing.T) {
+	// Keys that Path would split are quoted, so every reported path leads back to its value
+	doc := mustParse(t, `{"a.b": {"c[0]": [1, {"": null}]}, "d]": true, "e": {"f": "x"}}`)
+	var paths []string
+	err := doc.Walk(func(path string, value JSONValue) error {
+		paths = append(paths, path)
+		if found, err := doc.Path(path); err != nil || !found.Equal(value) {
+			t.Errorf("Path(%q) = %s, %v, want %s", path, found, err, value)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"", `["a.b"]`, `["a.b"]["c[0]"]`, `["a.b"]["c[0]"][0]`, `["a.b"]["c[0]"][1]`, `["a.b"]["c[0]"][1][""]`,
+		"d]", "e", "e.f",
+	}
+	if !slices.Equal(paths, want) {
+		t.Errorf("Walk() paths = %q, want %q", paths, want)
+	}
+}
+
+func TestWalkStopsOnError(t *testing.T) {
+	errFound := errors.New("found")
+	visited := 0
+	err := mustParse(t, sampleDocument).Walk(func(path string, value JSONValue) error {
+		visited++
+		if path == "address.city" {
+			return errFound
+		}
+		return nil
+	})
+	if err != errFound {
+		t.Errorf("Walk() error = %v, want %v", err, errFound)
+	}
+	// The root, name, age, email, active, address and address.city
+	if visited != 7 {
+		t.Errorf("Walk() visited %d values, want 7", visited)
+	}
+}
+
+func TestMapDropsKeys(t *testing.T) {
+	original := mustParse(t, `{"name": "a", "email": "a@x", "contacts": [{"email": "b@x", "id": 2}, "email"]}`)
+	mapped := original.Map(func(path string, value JSONValue) (JSONValue, bool) {
+		return value, path != "email" && !strings.HasSuffix(path, ".email")
+	})
+
+	if got, want := mapped.String(), `{"name":"a","contacts":[{"id":2},"email"]}`; got != want {
+		t.Errorf("Map() = %s, want %s", got, want)
+	}
+	if _, ok := original.Get("email"); !ok {
+		t.Error("Map() changed the original tree")
+	}
+
+	sample := mustParse(t, sampleDocument).Map(func(path string, value JSONValue) (JSONValue, bool) {
+		return value, path != "email"
+	})
+	if _, ok := sample.Get("email"); ok || sample.Len() != 5 {
+		t.Errorf("Map() on the sample document = %s, want it without email", sample)
+	}
+}
+
+func TestMapTransformsValues(t *testing.T) {
+	mapped := mustParse(t, `[1, [2], "x"]`).Map(func(path string, value JSONValue) (JSONValue, bool) {
+		if value.Kind == KindNumber {
+			value.Value = value.Value.(float64) * 10
+		}
+		return value, path != "[2]"
+	})
+	if got, want := mapped.String(), `[10,[20]]`; got != want {
+		t.Errorf("Map() = %s, want %s", got, want)
+	}
+
+	root := mustParse(t, `{}`).Map(func(string, JSONValue) (JSONValue, bool) { return JSONValue{}, false })
+	if !root.IsNull() {
+		t.Errorf("Map() dropping the root = %s, want null", root)
+	}
+}
@@ -0,0 +1,147 @@
+package jsonparser
+
+import (
+	"bytes"
+	"io"
+)
+
+// windowSize is the number of bytes read from an io.ReaderAt at a time
+const windowSize = 64 << 10
+
+// window reads the input of a parser from an io.ReaderAt a piece at a time,
+// so only the part being parsed is held in memory
+type window struct {
+	r    io.ReaderAt
+	size int64 // total input size
+	pos  int64 // offset of the next byte to read into the buffer
+	err  error // first read error, ending the input early
+
+	// A window over a stream reads it in order instead of reading r, and only
+	// learns its size once it ends. As read bytes cannot be read again, their
+	// lines are counted before the buffer drops them.
+	stream       io.Reader
+	ended        bool
+	recent       []byte // buffered bytes after the last refill, starting at recentOffset
+	recentOffset int
+	lines        int // newlines before recentOffset
+	lineStart    int // offset just past the last of those newlines
+}
+
+// NewJSONParserFromReaderAt creates a new JSONParser reading its size bytes of
+// input from r, such as an *os.File. Instead of loading the whole input, the
+// parser only buffers a window of it at a time, which suits very large files.
+func NewJSONParserFromReaderAt(r io.ReaderAt, size int64) *JSONParser {
+	p := &JSONParser{input: &bytes.Buffer{}, window: &window{r: r, size: size}, currentToken: ""}
+	p.skipBOM()
+
+	return p
+}
+
+// fill appends the next pieces of input to buf until it holds at least n bytes
+// or the input is exhausted
+func (w *window) fill(buf *bytes.Buffer, n int) {
+	if w.stream != nil {
+		w.fillStream(buf, n)
+		return
+	}
+
+	for buf.Len() < n && w.pos < w.size && w.err == nil {
+		chunk := int64(windowSize)
+		if remaining := w.size - w.pos; remaining < chunk {
+			chunk = remaining
+		}
+
+		// Read straight into the buffer's spare capacity. Growing first lets the
+		// buffer slide its unread bytes to the front instead of growing forever.
+		buf.Grow(int(chunk))
+		data := buf.AvailableBuffer()[:chunk]
+		read, err := w.r.ReadAt(data, w.pos)
+		buf.Write(data[:read])
+		w.pos += int64(read)
+		if err == io.EOF {
+			// The input is shorter than its stated size
+			w.size = w.pos
+		} else if err != nil {
+			w.err = err
+		}
+	}
+}
+
+// fillStream is fill for a window over a stream. It reads whatever the stream
+// has ready, so it only waits for more input when fewer than n bytes are buffered.
+func (w *window) fillStream(buf *bytes.Buffer, n int) {
+	for buf.Len() < n && !w.ended && w.err == nil {
+		// Count the lines of the bytes consumed since the last refill before
+		// growing the buffer can overwrite them
+		consumed := int(w.pos) - buf.Len()
+		w.countLines(w.recent[:consumed-w.recentOffset])
+
+		buf.Grow(windowSize)
+		data := buf.AvailableBuffer()[:windowSize]
+		read, err := w.stream.Read(data)
+		buf.Write(data[:read])
+		w.pos += int64(read)
+		w.recent, w.recentOffset = buf.Bytes(), consumed
+		if err == io.EOF {
+			w.ended = true
+			w.size = w.pos
+		} else if err != nil {
+			w.err = err
+		}
+	}
+}
+
+// countLines adds the newlines of consumed, the bytes starting at recentOffset, to the line count
+func (w *window) countLines(consumed []byte) {
+	if last := bytes.LastIndexByte(consumed, '\n'); last >= 0 {
+		w.lines += bytes.Count(consumed, []byte{'\n'})
+		w.lineStart = w.recentOffset + last + 1
+	}
+}
+
+// position converts a byte offset into a 1-based line and column by reading
+// the input up to it again
+func (w *window) position(offset int) (line, column int) {
+	if w.stream != nil {
+		return w.streamPosition(offset)
+	}
+	if int64(offset) > w.size {
+		offset = int(w.size)
+	}
+	line, lineStart := 1, 0
+	data := make([]byte, windowSize)
+	for pos := 0; pos < offset; {
+		chunk := len(data)
+		if offset-pos < chunk {
+			chunk = offset - pos
+		}
+		read, _ := w.r.ReadAt(data[:chunk], int64(pos))
+		if read == 0 {
+			break
+		}
+		for i, c := range data[:read] {
+			if c == '\n' {
+				line++
+				lineStart = pos + i + 1
+			}
+		}
+		pos += read
+	}
+
+	return line, offset - lineStart + 1
+}
+
+// streamPosition is position for a window over a stream. Offsets before the
+// last refill, which only a token spanning it reports, get the refill's line.
+func (w *window) streamPosition(offset int) (line, column int) {
+	line, lineStart := 1+w.lines, w.lineStart
+	if seen := offset - w.recentOffset; seen > 0 {
+		consumed := w.recent[:min(seen, len(w.recent))]
+		if last := bytes.LastIndexByte(consumed, '\n'); last >= 0 {
+			line += bytes.Count(consumed, []byte{'\n'})
+			lineStart = w.recentOffset + last + 1
+		}
+	}
+
+	return line, max(offset-lineStart, 0) + 1
+}
@@ -0,0 +1,88 @@
+package jsonparser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTempFile writes data to a file in a temporary directory and opens it
+func writeTempFile(t *testing.T, data string) *os.File {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "input.json")
+	if err := os.WriteFile(name, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+// largeDocument returns an array of records spanning several windows, with a
+// long string so that a single token crosses a window boundary
+func largeDocument() string {
+	record := `{"id": 12345, "name": "record", "tags": ["a", "b"]},` + "\n"
+	n := 3 * windowSize / len(record)
+
+	return "[" + strings.Repeat(record, n) + `"` + strings.Repeat("x", windowSize) + `"]`
+}
+
+func TestParseFromReaderAtLargeFile(t *testing.T) {
+	input := largeDocument()
+	f := writeTempFile(t, input)
+
+	value, err := NewJSONParserFromReaderAt(f, int64(len(input))).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if want := mustParse(t, input); !value.Equal(want) {
+		t.Errorf("Parse() from a file does not match Parse() of its contents")
+	}
+}
+
+func TestParseFromReaderAtErrorPosition(t *testing.T) {
+	input := largeDocument()
+	input = input[:len(input)-1] + ",]"
+	f := writeTempFile(t, input)
+
+	_, err := NewJSONParserFromReaderAt(f, int64(len(input))).Parse()
+	want := parseError(t, input)
+	if err == nil || err.Error() != want {
+		t.Errorf("Parse() error = %v, want %q", err, want)
+	}
+	if !strings.Contains(want, "trailing comma") {
+		t.Errorf("Parse() error = %q, want a trailing comma error", want)
+	}
+}
+
+// failingReaderAt fails every read at or past offset with err
+type failingReaderAt struct {
+	data   string
+	offset int64
+	err    error
+}
+
+func (r *failingReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	if off+int64(len(b)) > r.offset {
+		n := copy(b, r.data[off:max(off, r.offset)])
+		return n, r.err
+	}
+	return copy(b, r.data[off:]), nil
+}
+
+func TestParseFromReaderAtReadError(t *testing.T) {
+	input := largeDocument()
+	readErr := errors.New("disk error")
+	r := &failingReaderAt{data: input, offset: windowSize + 10, err: readErr}
+
+	_, err := NewJSONParserFromReaderAt(r, int64(len(input))).Parse()
+	if !errors.Is(err, readErr) {
+		t.Errorf("Parse() error = %v, want %v", err, readErr)
+	}
+}